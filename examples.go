@@ -5,6 +5,7 @@ import (
 	"futurae.com/smallworlds/graph/grid"
 	"futurae.com/smallworlds/graph/random"
 	"futurae.com/smallworlds/graph/ring"
+	"futurae.com/smallworlds/graph/scalefree"
 	"futurae.com/smallworlds/world"
 )
 
@@ -42,6 +43,16 @@ func ExampleRingSmallWorldGraph() graph.Graph {
 		WithDistantEdges()
 }
 
+// ExampleScaleFreeGraph generates a scale-free graph based
+// on the Barabási-Albert model. Each of the 1000 nodes links to 3
+// existing nodes, and with probability 0.5 each link is followed by
+// closing a triangle (the Holme-Kim variant).
+func ExampleScaleFreeGraph() graph.Graph {
+	return scalefree.NewGraph(3).
+		WithTriadProb(0.5).
+		WithNodes(1000)
+}
+
 // ExampleWalks sets up a world using the given graph.
 // We then generate a random walk of length 10,
 // Followed by 3 shortest path from the node 0 to node 10.
@@ -81,5 +92,5 @@ func ExampleAgentWalks(w *world.World) []world.Walk {
 	a.Explore()               // Agent takes a random walk from Node 2.
 	a.VisitAddressOrExplore() // Agent either visits one of his addresses, or will take another random walk.
 
-	return a.History
+	return a.History.Walks()
 }
@@ -0,0 +1,161 @@
+package graph
+
+// ConnectedComponents returns the weakly connected components of the
+// graph, i.e. the components connected when edge directions are ignored.
+// Components are ordered by their first nodes, and the nodes of a
+// component keep their order in g.Nodes().
+func ConnectedComponents(g Graph) [][]Node {
+	nodes, adj := indexed(g, true)
+
+	label := make([]int, len(nodes), len(nodes))
+	for i := range label {
+		label[i] = -1
+	}
+
+	count := 0
+	stack := make([]int, 0)
+	for i := range nodes {
+		if label[i] >= 0 {
+			continue
+		}
+
+		label[i] = count
+		stack = append(stack[:0], i)
+		for len(stack) > 0 {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, v := range adj[u] {
+				if label[v] < 0 {
+					label[v] = count
+					stack = append(stack, v)
+				}
+			}
+		}
+		count++
+	}
+	return group(nodes, label, count)
+}
+
+// StronglyConnectedComponents returns the strongly connected components
+// of the graph, in which every node can reach every other node along
+// directed edges. It implements Tarjan's algorithm. Components are
+// ordered by their first nodes, and the nodes of a component keep
+// their order in g.Nodes().
+func StronglyConnectedComponents(g Graph) [][]Node {
+	nodes, adj := indexed(g, false)
+	n := len(nodes)
+
+	index := make([]int, n, n)
+	low := make([]int, n, n)
+	onStack := make([]bool, n, n)
+	label := make([]int, n, n)
+	for i := range index {
+		index[i] = -1
+	}
+
+	// frame is a node on the call stack of the recursive formulation,
+	// with the position of the next neighbour to visit.
+	type frame struct{ node, next int }
+
+	next, count := 0, 0
+	stack := make([]int, 0)
+	for root := 0; root < n; root++ {
+		if index[root] >= 0 {
+			continue
+		}
+
+		calls := []frame{{root, 0}}
+		index[root], low[root] = next, next
+		next++
+		stack = append(stack, root)
+		onStack[root] = true
+
+		for len(calls) > 0 {
+			f := &calls[len(calls)-1]
+			u := f.node
+
+			if f.next < len(adj[u]) {
+				v := adj[u][f.next]
+				f.next++
+
+				switch {
+				case index[v] < 0:
+					index[v], low[v] = next, next
+					next++
+					stack = append(stack, v)
+					onStack[v] = true
+					calls = append(calls, frame{v, 0})
+				case onStack[v] && index[v] < low[u]:
+					low[u] = index[v]
+				}
+				continue
+			}
+
+			calls = calls[:len(calls)-1]
+			if len(calls) > 0 {
+				parent := calls[len(calls)-1].node
+				if low[u] < low[parent] {
+					low[parent] = low[u]
+				}
+			}
+
+			if low[u] == index[u] {
+				for {
+					v := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[v] = false
+					label[v] = count
+					if v == u {
+						break
+					}
+				}
+				count++
+			}
+		}
+	}
+	return group(nodes, label, count)
+}
+
+// indexed numbers the nodes of g, and returns their neighbourhoods.
+// Undirected neighbourhoods hold the ends of edges in both directions.
+func indexed(g Graph, undirected bool) ([]Node, [][]int) {
+	nodes := g.Nodes()
+	ids := make(map[string]int, len(nodes))
+	for i, n := range nodes {
+		ids[n.String()] = i
+	}
+
+	adj := make([][]int, len(nodes), len(nodes))
+	for _, e := range g.Edges() {
+		from, ok1 := ids[e.From().String()]
+		to, ok2 := ids[e.To().String()]
+		if !ok1 || !ok2 {
+			continue
+		}
+
+		adj[from] = append(adj[from], to)
+		if undirected {
+			adj[to] = append(adj[to], from)
+		}
+	}
+	return nodes, adj
+}
+
+// group collects the nodes by their labels, ordering the groups by their
+// first nodes.
+func group(nodes []Node, label []int, count int) [][]Node {
+	ids := make([]int, count, count)
+	for i := range ids {
+		ids[i] = -1
+	}
+
+	groups := make([][]Node, 0, count)
+	for i, n := range nodes {
+		if ids[label[i]] < 0 {
+			ids[label[i]] = len(groups)
+			groups = append(groups, make([]Node, 0))
+		}
+		groups[ids[label[i]]] = append(groups[ids[label[i]]], n)
+	}
+	return groups
+}
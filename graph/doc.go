@@ -1,7 +1,7 @@
 // Package graph provides interface types to access nodes and edges
 // in generated graphs.
 //
-// The _grid_, _random_, and _ring_ sub-packages provide graph generation
-// APIs.
+// The _grid_, _random_, _ring_, and _scalefree_ sub-packages provide graph
+// generation APIs, and the _io_ sub-package reads and writes graph files.
 
 package graph
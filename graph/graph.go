@@ -15,6 +15,22 @@ type Edge interface {
 	To() Node
 }
 
+// WeightedEdge is an Edge with a cost of traversing it, e.g.
+// a travel time or a distance. Weights are expected to be positive.
+type WeightedEdge interface {
+	Edge
+	Weight() float64
+}
+
+// Weight returns the weight of the given edge, or 1 if the
+// edge is not weighted.
+func Weight(e Edge) float64 {
+	if w, ok := e.(WeightedEdge); ok {
+		return w.Weight()
+	}
+	return 1
+}
+
 // Node is an interface that has a String representation, and
 // nodes are equivalent if they have the same String representations.
 type Node interface {
@@ -31,6 +47,21 @@ func Contains(nodes []Node, node Node) bool {
 	return false
 }
 
+// D3Options customise the D3 JSON object of a graph.
+type D3Options struct {
+	// Group assigns nodes to groups, e.g. communities or the values
+	// of a context feature. Nodes are in group 1 if it is nil.
+	Group func(Node) int
+
+	// Size sets node sizes, e.g. agent visit counts.
+	// Nodes have no size if it is nil.
+	Size func(Node) float64
+
+	// Weighted sets the link values to the edge weights
+	// rather than to 1.
+	Weighted bool
+}
+
 // D3Json function maps a Graph into a JSON object that
 // D3 JavaScript library can display.
 //
@@ -39,12 +70,30 @@ func Contains(nodes []Node, node Node) bool {
 //		"links":[{"source":"ID", "target":"ID", "value":1}]
 //	}
 func D3Json(w Graph) map[string]interface{} {
+	return D3JsonWith(w, D3Options{})
+}
+
+// D3JsonWith maps a Graph into a JSON object that D3 JavaScript
+// library can display, with the node groups, node sizes and link
+// values given by opts.
+//
+//	Schema ::= {
+//		"nodes": [{"id":"ID", "group": 1, "size": 1.0}],
+//		"links":[{"source":"ID", "target":"ID", "value":1}]
+//	}
+func D3JsonWith(w Graph, opts D3Options) map[string]interface{} {
 	nodesObj := make([]interface{}, 0, 0)
 
 	for _, node := range w.Nodes() {
 		nodeObj := make(map[string]interface{})
 		nodeObj["id"] = node.String()
-		nodeObj["group"] = 1 // will be cluster id
+		nodeObj["group"] = 1
+		if opts.Group != nil {
+			nodeObj["group"] = opts.Group(node)
+		}
+		if opts.Size != nil {
+			nodeObj["size"] = opts.Size(node)
+		}
 
 		nodesObj = append(nodesObj, nodeObj)
 	}
@@ -56,6 +105,9 @@ func D3Json(w Graph) map[string]interface{} {
 		edgeObj["source"] = e.From().String()
 		edgeObj["target"] = e.To().String()
 		edgeObj["value"] = 1
+		if opts.Weighted {
+			edgeObj["value"] = Weight(e)
+		}
 
 		linksObj = append(linksObj, edgeObj)
 	}
@@ -1,6 +1,10 @@
 package grid
 
-import "futurae.com/smallworlds/graph"
+import (
+	"sort"
+
+	"futurae.com/smallworlds/graph"
+)
 
 type edge struct {
 	from Position
@@ -15,6 +19,15 @@ func (e edge) To() graph.Node {
 	return e.to
 }
 
+// weightedEdge weighs an edge by the Manhattan distance between its ends.
+type weightedEdge struct {
+	edge
+}
+
+func (e weightedEdge) Weight() float64 {
+	return float64(e.from.distance(e.to))
+}
+
 type edges map[int]map[int]map[int]map[int]struct{}
 
 func (es edges) add(from Position, to Position) {
@@ -38,6 +51,8 @@ func (es edges) addDirection(from Position, to Position) {
 	es[from.x][from.y][to.x][to.y] = struct{}{}
 }
 
+// slice returns the edges ordered by their from, and then to positions.
+// The stable order keeps seeded graph constructions reproducible.
 func (es edges) slice() [][]Position {
 	acc := make([][]Position, 0, 0)
 
@@ -50,6 +65,13 @@ func (es edges) slice() [][]Position {
 			}
 		}
 	}
+
+	sort.Slice(acc, func(i, j int) bool {
+		if acc[i][0].equal(acc[j][0]) {
+			return acc[i][1].less(acc[j][1])
+		}
+		return acc[i][0].less(acc[j][0])
+	})
 	return acc
 }
 
@@ -15,12 +15,15 @@ import (
 // that represents a 2d matrix. Graph edges are stored as a set of (from, to)
 // tuples. The set data structure is implemented as a map of maps in the _edges_
 // structure.
+//
+// Edges are unweighted unless the graph is built WithWeights.
 type Graph struct {
-	LenX  int
-	LenY  int
-	nodes positions
-	edges edges
-	rand  *rand.Rand
+	LenX     int
+	LenY     int
+	nodes    positions
+	edges    edges
+	weighted bool
+	rand     *rand.Rand
 }
 
 // NewGraph returns a new empty graph with the given
@@ -43,6 +46,15 @@ func (w *Graph) WithSeed(seed int64) *Graph {
 	return w
 }
 
+// WithWeights is a builder that weighs every edge by the Manhattan
+// distance between its ends. Distant edges thus cost more to traverse
+// than short ones.
+func (w *Graph) WithWeights() *Graph {
+	w.weighted = true
+
+	return w
+}
+
 // WithAllNodes builds a node at every position in the grid.
 // I.e. it creates LenX*LenY nodes.
 func (w *Graph) WithAllNodes() *Graph {
@@ -82,26 +94,99 @@ func (w *Graph) WithDropout(p float64) *Graph {
 	return w
 }
 
+// WithConnectivity is a builder that repairs the connectivity of the graph,
+// e.g. after WithDropout, so that every node can reach every other node.
+// Every strongly connected component but the largest one is linked to the
+// largest one by an edge in both directions, between the closest pair of
+// their nodes.
+func (w *Graph) WithConnectivity() *Graph {
+	components := graph.StronglyConnectedComponents(w)
+
+	largest := 0
+	for i, c := range components {
+		if len(c) > len(components[largest]) {
+			largest = i
+		}
+	}
+
+	dist, nearest := w.nearest(components[largest])
+	for i, c := range components {
+		if i == largest {
+			continue
+		}
+
+		from := c[0].(Position)
+		for _, n := range c[1:] {
+			if p := n.(Position); dist[w.index(p)] < dist[w.index(from)] {
+				from = p
+			}
+		}
+		w.edges.add(from, nearest[w.index(from)])
+	}
+
+	return w
+}
+
+// nearest returns the distance from every position of the grid to the
+// closest of the positions qs, and that position, by a breadth-first search
+// from all of qs at once. Ties are broken by the order of qs, and the search
+// takes O(LenX*LenY) time regardless of the number of components.
+func (w *Graph) nearest(qs []graph.Node) ([]int, []Position) {
+	dist := make([]int, w.LenX*w.LenY)
+	nearest := make([]Position, w.LenX*w.LenY)
+	for i := range dist {
+		dist[i] = -1
+	}
+
+	queue := make([]Position, 0, len(dist))
+	for _, q := range qs {
+		p := q.(Position)
+		if dist[w.index(p)] < 0 {
+			dist[w.index(p)] = 0
+			nearest[w.index(p)] = p
+			queue = append(queue, p)
+		}
+	}
+
+	for i := 0; i < len(queue); i++ {
+		p := queue[i]
+		for _, q := range []Position{at(p.x-1, p.y), at(p.x+1, p.y), at(p.x, p.y-1), at(p.x, p.y+1)} {
+			if w.valid(q) && dist[w.index(q)] < 0 {
+				dist[w.index(q)] = dist[w.index(p)] + 1
+				nearest[w.index(q)] = nearest[w.index(p)]
+				queue = append(queue, q)
+			}
+		}
+	}
+	return dist, nearest
+}
+
+// index returns the index of the position in row-major order.
+func (w *Graph) index(p Position) int {
+	return p.x*w.LenY + p.y
+}
+
 // WithDistantEdges adds q edges from every node. The ends
 // are chosen given the likelihood defined as distance(from, to)^(-1*r).
 func (w *Graph) WithDistantEdges(q int, r int) *Graph {
-	for _, from := range w.nodes.slice() {
-		w.addDistantEdgesFrom(from, q, r)
+	nodes := w.nodes.slice()
+	for _, from := range nodes {
+		w.addDistantEdgesFrom(from, q, r, nodes)
 	}
 
 	return w
 }
 
-func (w *Graph) addDistantEdgesFrom(from Position, q int, r int) {
+func (w *Graph) addDistantEdgesFrom(from Position, q int, r int, nodes []Position) {
 	if !w.valid(from) {
 		return
 	}
 
-	normConst := w.normalizingConstFor(from, r)
+	normConst := normalizingConst(from, r, nodes)
 	added := 0
 
 	for added < q {
-		for _, to := range w.nodes.slice() {
+		for _, to := range nodes {
 			if (!from.equal(to)) && (added < q) && (!w.edges.contains(from, to)) {
 				p := math.Pow(float64(from.distance(to)), float64(-1*r)) / normConst
 
@@ -123,20 +208,31 @@ func (w *Graph) Nodes() []graph.Node {
 	return ns
 }
 
-// Edges exports the edges as a slice of Edges.
+// Edges exports the edges as a slice of Edges. If the graph is
+// weighted, the edges are graph.WeightedEdges.
 func (w *Graph) Edges() []graph.Edge {
 	es := make([]graph.Edge, 0, 0)
 	for _, e := range w.edges.slice() {
-		es = append(es, edge{e[0], e[1]})
+		if w.weighted {
+			es = append(es, weightedEdge{edge{e[0], e[1]}})
+		} else {
+			es = append(es, edge{e[0], e[1]})
+		}
 	}
 	return es
 }
 
 func (w *Graph) normalizingConstFor(node Position, r int) float64 {
+	return normalizingConst(node, r, w.nodes.slice())
+}
+
+func normalizingConst(node Position, r int, nodes []Position) float64 {
 	c := 0.0
 
-	for _, d := range w.nodes.distancesFrom(node) {
-		c = c + math.Pow(float64(d), float64(-1*r))
+	for _, to := range nodes {
+		if !node.equal(to) {
+			c = c + math.Pow(float64(node.distance(to)), float64(-1*r))
+		}
 	}
 
 	return c
@@ -54,6 +54,27 @@ func Test_D3Object(t *testing.T) {
 	})
 }
 
+func Test_D3ObjectWith(t *testing.T) {
+	g := NewGraph(3, 1).WithAllNodes().WithShortEdges(2).WithWeights()
+	o := graph.D3JsonWith(g, graph.D3Options{
+		Group:    func(n graph.Node) int { return n.(Position).x },
+		Size:     func(n graph.Node) float64 { return 0.5 },
+		Weighted: true,
+	})
+
+	assert.Contains(t, o["nodes"], map[string]interface{}{
+		"id":    "(2,0)",
+		"group": 2,
+		"size":  0.5,
+	})
+
+	assert.Contains(t, o["links"], map[string]interface{}{
+		"source": "(0,0)",
+		"target": "(2,0)",
+		"value":  2.0,
+	})
+}
+
 func Test_normalizingConst0(t *testing.T) {
 	q := NewGraph(4, 4).
 		WithAllNodes().
@@ -102,3 +123,56 @@ func Test_10000_Nodes(t *testing.T) {
 
 	assert.Len(t, q.edges.slice(), 460)
 }
+
+func Test_WithWeights(t *testing.T) {
+	q := NewGraph(4, 4).
+		WithSeed(42).
+		WithAllNodes().
+		WithShortEdges(2).
+		WithWeights()
+
+	for _, e := range q.Edges() {
+		d := e.From().(Position).distance(e.To().(Position))
+		assert.Equal(t, float64(d), graph.Weight(e))
+	}
+	assert.Equal(t, 1.0, graph.Weight(NewGraph(2, 2).WithAllNodes().WithShortEdges(1).Edges()[0]))
+}
+
+func Test_WithSeed_Reproducible(t *testing.T) {
+	build := func() *Graph {
+		return NewGraph(6, 6).
+			WithSeed(42).
+			WithAllNodes().
+			WithShortEdges(1).
+			WithDistantEdges(2, 2).
+			WithDropout(0.3)
+	}
+
+	assert.Equal(t, build().Nodes(), build().Nodes())
+	assert.Equal(t, build().Edges(), build().Edges())
+}
+
+func Test_WithConnectivity(t *testing.T) {
+	q := NewGraph(10, 10).
+		WithSeed(42).
+		WithAllNodes().
+		WithShortEdges(1).
+		WithDropout(0.5)
+	components := graph.StronglyConnectedComponents(q)
+	assert.Greater(t, len(components), 1)
+
+	// the nearest positions match those of an exhaustive search
+	dist, _ := q.nearest(components[0])
+	for _, n := range q.Nodes() {
+		d := q.LenX + q.LenY
+		for _, m := range components[0] {
+			if n.(Position).distance(m.(Position)) < d {
+				d = n.(Position).distance(m.(Position))
+			}
+		}
+		assert.Equal(t, d, dist[q.index(n.(Position))])
+	}
+
+	q.WithConnectivity()
+	assert.Len(t, graph.StronglyConnectedComponents(q), 1)
+}
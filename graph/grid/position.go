@@ -20,6 +20,14 @@ func (p Position) equal(q Position) bool {
 	return (p.x == q.x) && (p.y == q.y)
 }
 
+// less orders positions by their x, and then y coordinates.
+func (p Position) less(q Position) bool {
+	if p.x == q.x {
+		return p.y < q.y
+	}
+	return p.x < q.x
+}
+
 func (p Position) within(boundX, boundY int) bool {
 	return valid(p.x, boundX) && valid(p.y, boundY)
 }
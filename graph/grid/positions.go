@@ -1,5 +1,7 @@
 package grid
 
+import "sort"
+
 type positions map[int]map[int]struct{} // x->y->exists
 
 func positionsFrom(p Position, maxDistance int) positions {
@@ -16,17 +18,6 @@ func positionsFrom(p Position, maxDistance int) positions {
 	return acc
 }
 
-func (ps positions) distancesFrom(from Position) []int {
-	distances := make([]int, 0, 0)
-
-	for _, to := range ps.slice() {
-		if !from.equal(to) {
-			distances = append(distances, from.distance(to))
-		}
-	}
-
-	return distances
-}
 func (ps positions) add(p Position) {
 	_, ok := ps[p.x]
 	if !ok {
@@ -60,6 +51,8 @@ func (ps positions) merge(qs positions) {
 	}
 }
 
+// slice returns the positions ordered by their x, and then y coordinates.
+// The stable order keeps seeded graph constructions reproducible.
 func (ps positions) slice() []Position {
 	slice := make([]Position, 0, 0)
 
@@ -68,5 +61,9 @@ func (ps positions) slice() []Position {
 			slice = append(slice, at(x, y))
 		}
 	}
+
+	sort.Slice(slice, func(i, j int) bool {
+		return slice[i].less(slice[j])
+	})
 	return slice
 }
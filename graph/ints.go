@@ -38,3 +38,25 @@ func (e TupleEdge) From() Node {
 func (e TupleEdge) To() Node {
 	return e[1]
 }
+
+// WeightedIntEdge is an IntEdge with a weight.
+type WeightedIntEdge struct {
+	IntEdge
+	Cost float64
+}
+
+// Weight returns the cost of the edge.
+func (e WeightedIntEdge) Weight() float64 {
+	return e.Cost
+}
+
+// WeightedTupleEdge is a TupleEdge with a weight.
+type WeightedTupleEdge struct {
+	TupleEdge
+	Cost float64
+}
+
+// Weight returns the cost of the edge.
+func (e WeightedTupleEdge) Weight() float64 {
+	return e.Cost
+}
@@ -0,0 +1,74 @@
+package io
+
+import (
+	"bufio"
+	"encoding/json"
+	goio "io"
+	"strconv"
+
+	"futurae.com/smallworlds/graph"
+)
+
+// WriteCytoscapeJSON writes the graph in the Cytoscape.js JSON format
+//
+//	{"elements": {
+//		"nodes": [{"data": {"id": "ID", ...attributes}}],
+//		"edges": [{"data": {"id": "e0", "source": "ID", "target": "ID", "weight": 1}}]
+//	}}
+//
+// Node contexts of worlds, and attributes of read graphs, are written
+// as node data, unless they clash with the id.
+func WriteCytoscapeJSON(w goio.Writer, g graph.Graph, opts WriteOptions) error {
+	attributes := attributesOf(g)
+
+	b := bufio.NewWriter(w)
+	enc := json.NewEncoder(b)
+	b.WriteString(`{"elements":{"nodes":[`)
+
+	var err error
+	i := 0
+	eachNode(g, func(n graph.Node) {
+		if err != nil {
+			return
+		}
+		if i > 0 {
+			b.WriteString(",")
+		}
+		i++
+
+		data := make(map[string]interface{})
+		if attributes != nil {
+			for key, v := range attributes(n) {
+				data[key] = v
+			}
+		}
+		data["id"] = n.String()
+		err = enc.Encode(map[string]interface{}{"data": data})
+	})
+
+	b.WriteString(`],"edges":[`)
+	i = 0
+	eachEdge(g, opts, func(e graph.Edge) {
+		if err != nil {
+			return
+		}
+		if i > 0 {
+			b.WriteString(",")
+		}
+
+		data := map[string]interface{}{
+			"id":     "e" + strconv.Itoa(i),
+			"source": e.From().String(),
+			"target": e.To().String(),
+			"weight": graph.Weight(e),
+		}
+		i++
+		err = enc.Encode(map[string]interface{}{"data": data})
+	})
+	if err != nil {
+		return err
+	}
+
+	b.WriteString("]}}\n")
+	return b.Flush()
+}
@@ -0,0 +1,13 @@
+// Package io reads graphs from files in common graph formats: edge lists
+// (including CSV), GraphML, GML, and Pajek, and writes graphs to GraphML,
+// Graphviz DOT, GEXF, and Cytoscape JSON.
+//
+// Read graphs satisfy graph.Graph with StringNodes named by the node
+// identifiers in the file. Edge weights are read into graph.WeightedEdges,
+// and must be positive, and numeric node attributes can be attached to
+// worlds as contexts with world.World.WithAttributes.
+//
+// Writers write any graph.Graph to an io.Writer. Iterable graphs, such as
+// worlds, are streamed: every node and edge is written as it is enumerated.
+// Worlds are written with their node contexts as node attributes.
+package io
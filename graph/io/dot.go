@@ -0,0 +1,59 @@
+package io
+
+import (
+	"bufio"
+	"fmt"
+	goio "io"
+	"strconv"
+
+	"futurae.com/smallworlds/graph"
+)
+
+// WriteDOT writes the graph in the Graphviz DOT language. Node contexts
+// of worlds, and attributes of read graphs, are written as node attributes,
+// and weighted edges carry the edge attribute "weight".
+//
+// Graphviz only accepts integral weights for some layouts, e.g. dot,
+// so weights are best inspected with neato or fdp.
+func WriteDOT(w goio.Writer, g graph.Graph, opts WriteOptions) error {
+	attributes := attributesOf(g)
+	weighted := hasWeights(g)
+
+	kind, arrow := "digraph", "->"
+	if opts.Undirected {
+		kind, arrow = "graph", "--"
+	}
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "%s {\n", kind)
+
+	eachNode(g, func(n graph.Node) {
+		fmt.Fprintf(b, "  %s", strconv.Quote(n.String()))
+		if attributes != nil {
+			c := attributes(n)
+			keys := sortedKeys(c)
+			for i, key := range keys {
+				sep := ", "
+				if i == 0 {
+					sep = " ["
+				}
+				fmt.Fprintf(b, "%s%s=%s", sep, strconv.Quote(key), formatFloat(c[key]))
+			}
+			if len(keys) > 0 {
+				b.WriteString("]")
+			}
+		}
+		b.WriteString(";\n")
+	})
+
+	eachEdge(g, opts, func(e graph.Edge) {
+		fmt.Fprintf(b, "  %s %s %s", strconv.Quote(e.From().String()), arrow, strconv.Quote(e.To().String()))
+		if weighted {
+			fmt.Fprintf(b, " [weight=%s]", formatFloat(graph.Weight(e)))
+		}
+		b.WriteString(";\n")
+	})
+
+	b.WriteString("}\n")
+	return b.Flush()
+}
@@ -0,0 +1,95 @@
+package io
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	goio "io"
+	"strings"
+)
+
+// EdgeListOptions describe the layout of an edge list. Every record holds
+// the source and the target node, followed by an optional weight.
+type EdgeListOptions struct {
+	// Comma separates the fields of a record. The zero value separates
+	// fields by whitespace; any other value reads the list as CSV.
+	Comma rune
+
+	// Comment starts lines that are skipped. The zero value disables comments.
+	Comment rune
+
+	// Header skips the first record.
+	Header bool
+
+	// Directed reads every record as a single directed edge. Otherwise
+	// records are undirected edges, added in both directions.
+	Directed bool
+}
+
+// ReadEdgeList reads an edge list such as
+//
+//	# from to weight
+//	a b 1.5
+//	b c 2
+func ReadEdgeList(r goio.Reader, opts EdgeListOptions) (*Graph, error) {
+	records, err := readRecords(r, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	g := newGraph()
+	for i, record := range records {
+		if opts.Header && i == 0 {
+			continue
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("edge list record %d: expected at least 2 fields, found %d", i+1, len(record))
+		}
+
+		weight, weighted := 1.0, len(record) > 2 && record[2] != ""
+		if weighted {
+			weight, err = parseWeight(record[2])
+			if err != nil {
+				return nil, fmt.Errorf("edge list record %d: bad weight: %v", i+1, err)
+			}
+		}
+
+		g.addEdge(record[0], record[1], weight, weighted, opts.Directed)
+	}
+	return g, nil
+}
+
+// ReadCSV reads an undirected edge list in CSV format with a header row.
+func ReadCSV(r goio.Reader) (*Graph, error) {
+	return ReadEdgeList(r, EdgeListOptions{Comma: ',', Header: true})
+}
+
+func readRecords(r goio.Reader, opts EdgeListOptions) ([][]string, error) {
+	if opts.Comma != 0 {
+		c := csv.NewReader(r)
+		c.Comma = opts.Comma
+		c.Comment = opts.Comment
+		c.FieldsPerRecord = -1
+		c.TrimLeadingSpace = true
+
+		records, err := c.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("edge list: %v", err)
+		}
+		return records, nil
+	}
+
+	records := make([][]string, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || (opts.Comment != 0 && strings.HasPrefix(line, string(opts.Comment))) {
+			continue
+		}
+		records = append(records, strings.Fields(line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("edge list: %v", err)
+	}
+	return records, nil
+}
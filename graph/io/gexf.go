@@ -0,0 +1,68 @@
+package io
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	goio "io"
+
+	"futurae.com/smallworlds/graph"
+)
+
+// WriteGEXF writes the graph in the GEXF 1.3 format, as read by Gephi.
+// Node contexts of worlds, and attributes of read graphs, are written as
+// node attributes, and every edge carries its weight.
+func WriteGEXF(w goio.Writer, g graph.Graph, opts WriteOptions) error {
+	attributes := attributesOf(g)
+	keys := attributeKeys(g, attributes)
+
+	edgeType := "directed"
+	if opts.Undirected {
+		edgeType = "undirected"
+	}
+
+	b := bufio.NewWriter(w)
+	b.WriteString(xml.Header)
+	b.WriteString(`<gexf xmlns="http://gexf.net/1.3" version="1.3">` + "\n")
+	fmt.Fprintf(b, "  <graph mode=\"static\" defaultedgetype=\"%s\">\n", edgeType)
+
+	if len(keys) > 0 {
+		b.WriteString("    <attributes class=\"node\">\n")
+		for i, key := range keys {
+			fmt.Fprintf(b, "      <attribute id=\"%d\" title=\"%s\" type=\"double\"/>\n", i, escape(key))
+		}
+		b.WriteString("    </attributes>\n")
+	}
+
+	b.WriteString("    <nodes>\n")
+	eachNode(g, func(n graph.Node) {
+		id := escape(n.String())
+		fmt.Fprintf(b, "      <node id=\"%s\" label=\"%s\"", id, id)
+		if attributes == nil || len(attributes(n)) == 0 {
+			b.WriteString("/>\n")
+			return
+		}
+
+		b.WriteString("><attvalues>")
+		c := attributes(n)
+		for i, key := range keys {
+			if v, ok := c[key]; ok {
+				fmt.Fprintf(b, "<attvalue for=\"%d\" value=\"%s\"/>", i, formatFloat(v))
+			}
+		}
+		b.WriteString("</attvalues></node>\n")
+	})
+	b.WriteString("    </nodes>\n")
+
+	b.WriteString("    <edges>\n")
+	i := 0
+	eachEdge(g, opts, func(e graph.Edge) {
+		fmt.Fprintf(b, "      <edge id=\"%d\" source=\"%s\" target=\"%s\" weight=\"%s\"/>\n",
+			i, escape(e.From().String()), escape(e.To().String()), formatFloat(graph.Weight(e)))
+		i++
+	})
+	b.WriteString("    </edges>\n")
+
+	b.WriteString("  </graph>\n</gexf>\n")
+	return b.Flush()
+}
@@ -0,0 +1,214 @@
+package io
+
+import (
+	"bufio"
+	"fmt"
+	goio "io"
+	"strconv"
+	"unicode"
+)
+
+// gmlValue is either a scalar, kept as its literal text, or a list of
+// key-value pairs.
+type gmlValue struct {
+	text string
+	list []gmlPair
+}
+
+type gmlPair struct {
+	key   string
+	value gmlValue
+}
+
+func (v gmlValue) get(key string) (gmlValue, bool) {
+	for _, p := range v.list {
+		if p.key == key {
+			return p.value, true
+		}
+	}
+	return gmlValue{}, false
+}
+
+// ReadGML reads a graph in the GML format. Nodes are named by their
+// id, numeric node keys other than id become node attributes, and edge
+// weights are read from the edges' weight or value keys.
+func ReadGML(r goio.Reader) (*Graph, error) {
+	p, err := newGMLParser(r)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := p.list(false)
+	if err != nil {
+		return nil, err
+	}
+
+	root, ok := doc.get("graph")
+	if !ok || root.list == nil {
+		return nil, fmt.Errorf("gml: missing graph")
+	}
+
+	directed := false
+	if d, ok := root.get("directed"); ok {
+		directed = d.text == "1"
+	}
+
+	g := newGraph()
+	for _, pair := range root.list {
+		if pair.key != "node" {
+			continue
+		}
+
+		id, ok := pair.value.get("id")
+		if !ok {
+			return nil, fmt.Errorf("gml: node without id")
+		}
+		g.addNode(id.text)
+
+		for _, attr := range pair.value.list {
+			if attr.key == "id" || attr.value.list != nil {
+				continue
+			}
+			if v, err := strconv.ParseFloat(attr.value.text, 64); err == nil {
+				g.setAttribute(id.text, attr.key, v)
+			}
+		}
+	}
+
+	for _, pair := range root.list {
+		if pair.key != "edge" {
+			continue
+		}
+
+		source, ok1 := pair.value.get("source")
+		target, ok2 := pair.value.get("target")
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("gml: edge without source or target")
+		}
+
+		weight, weighted := 1.0, false
+		for _, key := range []string{"weight", "value"} {
+			w, ok := pair.value.get(key)
+			if !ok {
+				continue
+			}
+
+			weight, err = parseWeight(w.text)
+			if err != nil {
+				return nil, fmt.Errorf("gml: edge %s-%s: bad weight: %v", source.text, target.text, err)
+			}
+			weighted = true
+			break
+		}
+
+		g.addEdge(source.text, target.text, weight, weighted, directed)
+	}
+	return g, nil
+}
+
+type gmlParser struct {
+	tokens []gmlToken
+	pos    int
+}
+
+type gmlToken struct {
+	text   string
+	quoted bool
+	line   int
+}
+
+func newGMLParser(r goio.Reader) (*gmlParser, error) {
+	p := &gmlParser{}
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if err := p.tokenize(scanner.Text(), line); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("gml: %v", err)
+	}
+	return p, nil
+}
+
+// tokenize splits a line into keys, values and brackets.
+// Quoted strings must not span lines.
+func (p *gmlParser) tokenize(s string, line int) error {
+	rs := []rune(s)
+	for i := 0; i < len(rs); {
+		switch {
+		case unicode.IsSpace(rs[i]):
+			i++
+		case rs[i] == '#':
+			return nil
+		case rs[i] == '[' || rs[i] == ']':
+			p.tokens = append(p.tokens, gmlToken{text: string(rs[i]), line: line})
+			i++
+		case rs[i] == '"':
+			j := i + 1
+			for j < len(rs) && rs[j] != '"' {
+				j++
+			}
+			if j == len(rs) {
+				return fmt.Errorf("gml: line %d: unterminated string", line)
+			}
+			p.tokens = append(p.tokens, gmlToken{text: string(rs[i+1 : j]), quoted: true, line: line})
+			i = j + 1
+		default:
+			j := i
+			for j < len(rs) && !unicode.IsSpace(rs[j]) && rs[j] != '[' && rs[j] != ']' {
+				j++
+			}
+			p.tokens = append(p.tokens, gmlToken{text: string(rs[i:j]), line: line})
+			i = j
+		}
+	}
+	return nil
+}
+
+// list parses key-value pairs up to the closing bracket,
+// or up to the end of the input at the top level.
+func (p *gmlParser) list(nested bool) (gmlValue, error) {
+	v := gmlValue{list: make([]gmlPair, 0)}
+	for p.pos < len(p.tokens) {
+		key := p.tokens[p.pos]
+		p.pos++
+
+		if key.text == "]" && !key.quoted {
+			if !nested {
+				return v, fmt.Errorf("gml: line %d: unexpected ]", key.line)
+			}
+			return v, nil
+		}
+		if key.quoted || key.text == "[" {
+			return v, fmt.Errorf("gml: line %d: expected key, found %q", key.line, key.text)
+		}
+		if p.pos == len(p.tokens) {
+			return v, fmt.Errorf("gml: line %d: missing value for %s", key.line, key.text)
+		}
+
+		value := p.tokens[p.pos]
+		p.pos++
+
+		switch {
+		case value.text == "[" && !value.quoted:
+			l, err := p.list(true)
+			if err != nil {
+				return v, err
+			}
+			v.list = append(v.list, gmlPair{key: key.text, value: l})
+		case value.text == "]" && !value.quoted:
+			return v, fmt.Errorf("gml: line %d: missing value for %s", key.line, key.text)
+		default:
+			v.list = append(v.list, gmlPair{key: key.text, value: gmlValue{text: value.text}})
+		}
+	}
+
+	if nested {
+		return v, fmt.Errorf("gml: missing ]")
+	}
+	return v, nil
+}
@@ -0,0 +1,113 @@
+package io
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"futurae.com/smallworlds/graph"
+)
+
+// Graph holds the nodes, edges and node attributes read from a file.
+// Undirected edges are stored in both directions.
+type Graph struct {
+	nodes      []graph.Node
+	index      map[string]int
+	edges      []graph.Edge
+	attributes map[string]map[string]float64
+}
+
+func newGraph() *Graph {
+	return &Graph{
+		nodes:      make([]graph.Node, 0),
+		index:      make(map[string]int),
+		edges:      make([]graph.Edge, 0),
+		attributes: make(map[string]map[string]float64),
+	}
+}
+
+// Nodes returns the nodes in the order they were read.
+func (g *Graph) Nodes() []graph.Node {
+	ns := make([]graph.Node, len(g.nodes), len(g.nodes))
+	copy(ns, g.nodes)
+	return ns
+}
+
+// Edges returns the edges in the order they were read. Edges with
+// a weight in the file are graph.WeightedEdges.
+func (g *Graph) Edges() []graph.Edge {
+	es := make([]graph.Edge, len(g.edges), len(g.edges))
+	copy(es, g.edges)
+	return es
+}
+
+// Attributes returns the numeric attributes of the given node. Worlds
+// attach them to the nodes' contexts with world.World.WithAttributes.
+func (g *Graph) Attributes(n graph.Node) map[string]float64 {
+	c, ok := g.attributes[n.String()]
+	if !ok {
+		return map[string]float64{}
+	}
+	return c
+}
+
+func (g *Graph) addNode(id string) graph.Node {
+	if i, ok := g.index[id]; ok {
+		return g.nodes[i]
+	}
+
+	n := graph.StringNode(id)
+	g.index[id] = len(g.nodes)
+	g.nodes = append(g.nodes, n)
+	return n
+}
+
+func (g *Graph) hasNode(id string) bool {
+	_, ok := g.index[id]
+	return ok
+}
+
+// addEdge adds the edge, and its reverse if the edge is undirected.
+// A weight is only attached if the file provides one.
+func (g *Graph) addEdge(from, to string, weight float64, weighted, directed bool) {
+	f := g.addNode(from)
+	t := g.addNode(to)
+
+	g.edges = append(g.edges, newEdge(f, t, weight, weighted))
+	if !directed && from != to {
+		g.edges = append(g.edges, newEdge(t, f, weight, weighted))
+	}
+}
+
+// parseWeight parses an edge weight. Weights are costs, and worlds
+// reject edges that are free or pay to traverse, thus the weight must
+// be positive and finite.
+func parseWeight(s string) (float64, error) {
+	w, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if !(w > 0) || math.IsInf(w, 1) {
+		return 0, fmt.Errorf("weight %s is not positive and finite", s)
+	}
+	return w, nil
+}
+
+func newEdge(from, to graph.Node, weight float64, weighted bool) graph.Edge {
+	e := graph.TupleEdge{from, to}
+	if weighted {
+		return graph.WeightedTupleEdge{TupleEdge: e, Cost: weight}
+	}
+	return e
+}
+
+func (g *Graph) setAttribute(id, key string, value float64) {
+	g.addNode(id)
+
+	c, ok := g.attributes[id]
+	if !ok {
+		c = make(map[string]float64)
+		g.attributes[id] = c
+	}
+	c[key] = value
+}
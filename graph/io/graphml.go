@@ -0,0 +1,199 @@
+package io
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	goio "io"
+	"strconv"
+	"strings"
+
+	"futurae.com/smallworlds/graph"
+)
+
+type graphML struct {
+	Keys  []graphMLKey `xml:"key"`
+	Graph struct {
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID      string `xml:"id,attr"`
+	For     string `xml:"for,attr"`
+	Name    string `xml:"attr.name,attr"`
+	Type    string `xml:"attr.type,attr"`
+	Default string `xml:"default"`
+}
+
+// name returns the attr.name of the key, or its id if it has none.
+func (k graphMLKey) name() string {
+	if k.Name == "" {
+		return k.ID
+	}
+	return k.Name
+}
+
+// keysFor returns the keys that apply to the given kind of elements,
+// by their ids. Keys without a for attribute apply to all elements.
+func keysFor(keys []graphMLKey, kind string) map[string]graphMLKey {
+	ks := make(map[string]graphMLKey)
+	for _, k := range keys {
+		if k.For == "" || k.For == "all" || k.For == kind {
+			ks[k.ID] = k
+		}
+	}
+	return ks
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source   string        `xml:"source,attr"`
+	Target   string        `xml:"target,attr"`
+	Directed string        `xml:"directed,attr"`
+	Data     []graphMLData `xml:"data"`
+}
+
+// ReadGraphML reads a graph in the GraphML format. Numeric node data
+// become node attributes named by their keys' attr.name, and the edge
+// data named "weight" become edge weights. Data only count for the
+// elements that their keys are declared for, and elements without data
+// for a key take the key's default, if any. Nested graphs, hyperedges
+// and ports are not supported.
+func ReadGraphML(r goio.Reader) (*Graph, error) {
+	var doc graphML
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("graphml: %v", err)
+	}
+
+	nodeKeys := keysFor(doc.Keys, "node")
+	edgeKeys := keysFor(doc.Keys, "edge")
+
+	g := newGraph()
+	for _, n := range doc.Graph.Nodes {
+		g.addNode(n.ID)
+
+		values := make(map[string]string)
+		for id, k := range nodeKeys {
+			if k.Default != "" {
+				values[id] = k.Default
+			}
+		}
+		for _, d := range n.Data {
+			if _, ok := nodeKeys[d.Key]; ok {
+				values[d.Key] = d.Value
+			}
+		}
+
+		for id, value := range values {
+			if v, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				g.setAttribute(n.ID, nodeKeys[id].name(), v)
+			}
+		}
+	}
+
+	defaultWeight := ""
+	for _, k := range edgeKeys {
+		if strings.EqualFold(k.name(), "weight") && k.Default != "" {
+			defaultWeight = k.Default
+		}
+	}
+
+	for _, e := range doc.Graph.Edges {
+		directed := doc.Graph.EdgeDefault == "directed"
+		if e.Directed != "" {
+			directed = e.Directed == "true"
+		}
+
+		value := defaultWeight
+		for _, d := range e.Data {
+			if k, ok := edgeKeys[d.Key]; ok && strings.EqualFold(k.name(), "weight") {
+				value = d.Value
+			}
+		}
+
+		weight, weighted := 1.0, value != ""
+		if weighted {
+			v, err := parseWeight(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("graphml: edge %s-%s: bad weight: %v", e.Source, e.Target, err)
+			}
+			weight = v
+		}
+
+		g.addEdge(e.Source, e.Target, weight, weighted, directed)
+	}
+	return g, nil
+}
+
+// WriteGraphML writes the graph in the GraphML format. Node contexts
+// of worlds, and attributes of read graphs, are written as node data,
+// and weighted edges carry the edge data "weight".
+func WriteGraphML(w goio.Writer, g graph.Graph, opts WriteOptions) error {
+	attributes := attributesOf(g)
+	keys := attributeKeys(g, attributes)
+
+	b := bufio.NewWriter(w)
+	b.WriteString(xml.Header)
+	b.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	for i, key := range keys {
+		fmt.Fprintf(b, "  <key id=\"d%d\" for=\"node\" attr.name=\"%s\" attr.type=\"double\"/>\n", i, escape(key))
+	}
+	weighted := hasWeights(g)
+	if weighted {
+		b.WriteString(`  <key id="weight" for="edge" attr.name="weight" attr.type="double"/>` + "\n")
+	}
+
+	edgeDefault := "directed"
+	if opts.Undirected {
+		edgeDefault = "undirected"
+	}
+	fmt.Fprintf(b, "  <graph id=\"G\" edgedefault=\"%s\">\n", edgeDefault)
+
+	eachNode(g, func(n graph.Node) {
+		fmt.Fprintf(b, "    <node id=\"%s\"", escape(n.String()))
+		if attributes == nil || len(attributes(n)) == 0 {
+			b.WriteString("/>\n")
+			return
+		}
+
+		b.WriteString(">")
+		c := attributes(n)
+		for i, key := range keys {
+			if v, ok := c[key]; ok {
+				fmt.Fprintf(b, "<data key=\"d%d\">%s</data>", i, formatFloat(v))
+			}
+		}
+		b.WriteString("</node>\n")
+	})
+
+	eachEdge(g, opts, func(e graph.Edge) {
+		fmt.Fprintf(b, "    <edge source=\"%s\" target=\"%s\"", escape(e.From().String()), escape(e.To().String()))
+		if !weighted {
+			b.WriteString("/>\n")
+			return
+		}
+		fmt.Fprintf(b, "><data key=\"weight\">%s</data></edge>\n", formatFloat(graph.Weight(e)))
+	})
+
+	b.WriteString("  </graph>\n</graphml>\n")
+	return b.Flush()
+}
+
+// escape escapes the string for XML text and attribute values.
+func escape(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
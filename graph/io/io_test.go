@@ -0,0 +1,165 @@
+package io
+
+import (
+	"strings"
+	"testing"
+
+	"futurae.com/smallworlds/graph"
+	"futurae.com/smallworlds/world"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ReadEdgeList(t *testing.T) {
+	g, err := ReadEdgeList(strings.NewReader(`
+# from to weight
+a b 1.5
+b c
+`), EdgeListOptions{Comment: '#'})
+	assert.NoError(t, err)
+	assert.Equal(t, g.Nodes(), []graph.Node{graph.StringNode("a"), graph.StringNode("b"), graph.StringNode("c")})
+	assert.Len(t, g.Edges(), 4)
+	assert.Equal(t, graph.Weight(g.Edges()[0]), 1.5)
+	assert.Equal(t, graph.Weight(g.Edges()[1]), 1.5)
+	assert.Equal(t, graph.Weight(g.Edges()[2]), 1.0)
+
+	d, err := ReadEdgeList(strings.NewReader("a b\nb c\n"), EdgeListOptions{Directed: true})
+	assert.NoError(t, err)
+	assert.Len(t, d.Edges(), 2)
+
+	_, err = ReadEdgeList(strings.NewReader("a b x\n"), EdgeListOptions{})
+	assert.Error(t, err)
+	_, err = ReadEdgeList(strings.NewReader("a b 0\n"), EdgeListOptions{})
+	assert.Error(t, err)
+	_, err = ReadEdgeList(strings.NewReader("a b -1\n"), EdgeListOptions{})
+	assert.Error(t, err)
+	_, err = ReadEdgeList(strings.NewReader("a\n"), EdgeListOptions{})
+	assert.Error(t, err)
+}
+
+func Test_ReadCSV(t *testing.T) {
+	g, err := ReadCSV(strings.NewReader("from,to,weight\nlobby,office 1,2\nlobby,office 2,\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, g.Nodes(), []graph.Node{graph.StringNode("lobby"), graph.StringNode("office 1"), graph.StringNode("office 2")})
+	assert.Len(t, g.Edges(), 4)
+
+	w := world.NewWorld(g).WithAttributes(g)
+	assert.True(t, w.Weighted())
+	assert.Equal(t, w.Weight(graph.StringNode("office 1"), graph.StringNode("lobby")), 2.0)
+	assert.Equal(t, w.Weight(graph.StringNode("office 2"), graph.StringNode("lobby")), 1.0)
+}
+
+func Test_ReadGraphML(t *testing.T) {
+	g, err := ReadGraphML(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="node" attr.name="capacity" attr.type="double"/>
+  <key id="d1" for="node" attr.name="name" attr.type="string"/>
+  <key id="d2" for="edge" attr.name="weight" attr.type="double"/>
+  <graph id="G" edgedefault="undirected">
+    <node id="n0"><data key="d0">12</data><data key="d1">lobby</data></node>
+    <node id="n1"/>
+    <node id="n2"/>
+    <edge source="n0" target="n1"><data key="d2">3.5</data></edge>
+    <edge source="n1" target="n2" directed="true"/>
+  </graph>
+</graphml>`))
+	assert.NoError(t, err)
+	assert.Len(t, g.Nodes(), 3)
+	assert.Len(t, g.Edges(), 3)
+	assert.Equal(t, graph.Weight(g.Edges()[0]), 3.5)
+	assert.Equal(t, g.Attributes(graph.StringNode("n0")), map[string]float64{"capacity": 12})
+	assert.Len(t, g.Attributes(graph.StringNode("n1")), 0)
+
+	w := world.NewWorld(g).WithAttributes(g)
+	assert.Equal(t, w.Context(graph.StringNode("n0"))["capacity"], 12.0)
+	assert.True(t, w.HasEdge(graph.StringNode("n1"), graph.StringNode("n2")))
+	assert.False(t, w.HasEdge(graph.StringNode("n2"), graph.StringNode("n1")))
+
+	_, err = ReadGraphML(strings.NewReader("<graphml><graph>"))
+	assert.Error(t, err)
+	_, err = ReadGraphML(strings.NewReader(`<graphml>
+  <key id="w" for="edge" attr.name="weight"/>
+  <graph><node id="a"/><node id="b"/><edge source="a" target="b"><data key="w">-1</data></edge></graph>
+</graphml>`))
+	assert.Error(t, err)
+}
+
+func Test_ReadGraphML_Keys(t *testing.T) {
+	g, err := ReadGraphML(strings.NewReader(`<graphml>
+  <key id="c" for="node" attr.name="capacity"><default>5</default></key>
+  <key id="w" for="edge" attr.name="weight"><default>2</default></key>
+  <key id="e" for="edge" attr.name="length"/>
+  <graph edgedefault="directed">
+    <node id="a"><data key="c">12</data><data key="e">3</data></node>
+    <node id="b"/>
+    <edge source="a" target="b"/>
+    <edge source="b" target="a"><data key="w">4</data></edge>
+  </graph>
+</graphml>`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"capacity": 12}, g.Attributes(graph.StringNode("a")))
+	assert.Equal(t, map[string]float64{"capacity": 5}, g.Attributes(graph.StringNode("b")))
+	assert.Equal(t, 2.0, graph.Weight(g.Edges()[0]))
+	assert.Equal(t, 4.0, graph.Weight(g.Edges()[1]))
+}
+
+func Test_ReadGML(t *testing.T) {
+	g, err := ReadGML(strings.NewReader(`
+# a directed triangle
+graph [
+  directed 1
+  node [ id 1 label "lobby" capacity 12 ]
+  node [ id 2 ]
+  node [ id 3 graphics [ x 1.0 ] ]
+  edge [ source 1 target 2 weight 2.5 ]
+  edge [ source 2 target 3 ]
+  edge [ source 3 target 1 ]
+]`))
+	assert.NoError(t, err)
+	assert.Equal(t, g.Nodes(), []graph.Node{graph.StringNode("1"), graph.StringNode("2"), graph.StringNode("3")})
+	assert.Len(t, g.Edges(), 3)
+	assert.Equal(t, graph.Weight(g.Edges()[0]), 2.5)
+	assert.Equal(t, g.Attributes(graph.StringNode("1")), map[string]float64{"capacity": 12})
+	assert.Len(t, g.Attributes(graph.StringNode("3")), 0)
+
+	u, err := ReadGML(strings.NewReader(`graph [ node [ id 1 ] node [ id 2 ] edge [ source 1 target 2 ] ]`))
+	assert.NoError(t, err)
+	assert.Len(t, u.Edges(), 2)
+
+	_, err = ReadGML(strings.NewReader(`graph [ node [ id 1 ]`))
+	assert.Error(t, err)
+	_, err = ReadGML(strings.NewReader(`graph [ node [ id 1 ] node [ id 2 ] edge [ source 1 target 2 weight 0 ] ]`))
+	assert.Error(t, err)
+	_, err = ReadGML(strings.NewReader(`graph [ node [ label "a" ] ]`))
+	assert.Error(t, err)
+}
+
+func Test_ReadPajek(t *testing.T) {
+	g, err := ReadPajek(strings.NewReader(`% a small floor
+*Vertices 4
+1 "lobby" 0.1 0.2 0.0
+2 "office 1"
+3 "office 2"
+4
+*Arcs
+1 2 2.5
+*Edges
+2 3
+*Edgeslist
+1 3 4
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, g.Nodes(), []graph.Node{
+		graph.StringNode("lobby"), graph.StringNode("office 1"), graph.StringNode("office 2"), graph.StringNode("4"),
+	})
+	assert.Len(t, g.Edges(), 7)
+	assert.Equal(t, graph.Weight(g.Edges()[0]), 2.5)
+	assert.Equal(t, g.Attributes(graph.StringNode("lobby")), map[string]float64{"x": 0.1, "y": 0.2, "z": 0})
+
+	w := world.NewWorld(g).WithAttributes(g)
+	assert.True(t, w.HasEdge(graph.StringNode("lobby"), graph.StringNode("office 1")))
+	assert.False(t, w.HasEdge(graph.StringNode("office 1"), graph.StringNode("lobby")))
+	assert.True(t, w.HasEdge(graph.StringNode("4"), graph.StringNode("lobby")))
+
+	_, err = ReadPajek(strings.NewReader("1 2\n"))
+	assert.Error(t, err)
+}
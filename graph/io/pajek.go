@@ -0,0 +1,113 @@
+package io
+
+import (
+	"bufio"
+	"fmt"
+	goio "io"
+	"strconv"
+	"strings"
+)
+
+// ReadPajek reads a graph in the Pajek .net format. Vertices are named
+// by their labels, or by their numbers if they have none, and vertex
+// coordinates become the node attributes x, y and z. Arcs are directed
+// and edges undirected.
+func ReadPajek(r goio.Reader) (*Graph, error) {
+	g := newGraph()
+	names := make(map[string]string)
+	name := func(number string) string {
+		if n, ok := names[number]; ok {
+			return n
+		}
+		return number
+	}
+
+	section := ""
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "%") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "*") {
+			section = strings.ToLower(strings.Fields(text)[0])
+			continue
+		}
+
+		fields, err := pajekFields(text)
+		if err != nil {
+			return nil, fmt.Errorf("pajek: line %d: %v", line, err)
+		}
+
+		switch section {
+		case "*vertices":
+			number := fields[0]
+			names[number] = number
+			if len(fields) > 1 {
+				names[number] = fields[1]
+			}
+			g.addNode(names[number])
+
+			for i, key := range []string{"x", "y", "z"} {
+				if len(fields) <= i+2 {
+					break
+				}
+				v, err := strconv.ParseFloat(fields[i+2], 64)
+				if err != nil {
+					break
+				}
+				g.setAttribute(names[number], key, v)
+			}
+		case "*arcs", "*edges":
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("pajek: line %d: expected at least 2 fields, found %d", line, len(fields))
+			}
+
+			weight, weighted := 1.0, len(fields) > 2
+			if weighted {
+				weight, err = parseWeight(fields[2])
+				if err != nil {
+					return nil, fmt.Errorf("pajek: line %d: bad weight: %v", line, err)
+				}
+			}
+			g.addEdge(name(fields[0]), name(fields[1]), weight, weighted, section == "*arcs")
+		case "*arcslist", "*edgeslist":
+			for _, to := range fields[1:] {
+				g.addEdge(name(fields[0]), name(to), 1, false, section == "*arcslist")
+			}
+		default:
+			return nil, fmt.Errorf("pajek: line %d: unexpected data outside of a section", line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("pajek: %v", err)
+	}
+	return g, nil
+}
+
+// pajekFields splits a line at whitespace, keeping quoted labels together.
+func pajekFields(s string) ([]string, error) {
+	fields := make([]string, 0)
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimSpace(s) {
+		if s[0] == '"' {
+			end := strings.IndexByte(s[1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated label")
+			}
+			fields = append(fields, s[1:end+1])
+			s = s[end+2:]
+			continue
+		}
+
+		end := strings.IndexAny(s, " \t")
+		if end < 0 {
+			end = len(s)
+		}
+		fields = append(fields, s[:end])
+		s = s[end:]
+	}
+	return fields, nil
+}
@@ -0,0 +1,128 @@
+package io
+
+import (
+	"sort"
+	"strconv"
+
+	"futurae.com/smallworlds/graph"
+)
+
+// WriteOptions control how graphs are written.
+type WriteOptions struct {
+	// Undirected writes a pair of opposite edges as a single undirected
+	// edge, as generated graphs provide undirected edges in both directions.
+	// The weight of the first edge of the pair is written.
+	Undirected bool
+}
+
+// attributed graphs attach numeric attributes to their nodes, like
+// read graphs do, and worlds do with their contexts.
+type attributed interface {
+	Attributes(graph.Node) map[string]float64
+}
+
+// attributesOf returns the node attributes of the graph,
+// or nil if the graph has none.
+func attributesOf(g graph.Graph) func(graph.Node) map[string]float64 {
+	if a, ok := g.(attributed); ok {
+		return a.Attributes
+	}
+	return nil
+}
+
+// Iterable graphs enumerate their nodes and edges one at a time, without
+// collecting them, e.g. worlds. Writers stream iterable graphs in two passes:
+// the first finds the attribute keys and whether any edge is weighted, which
+// the formats declare up front, and the second writes every node and edge as
+// it is enumerated. Other graphs are written from their Nodes and Edges.
+type Iterable interface {
+	graph.Graph
+	EachNode(f func(n graph.Node))
+	EachEdge(f func(e graph.Edge))
+}
+
+// eachNode calls f with every node of the graph.
+func eachNode(g graph.Graph, f func(n graph.Node)) {
+	if it, ok := g.(Iterable); ok {
+		it.EachNode(f)
+		return
+	}
+	for _, n := range g.Nodes() {
+		f(n)
+	}
+}
+
+// eachEdge calls f with every edge to write. Undirected graphs keep the
+// first edge of every pair of opposite edges, which takes a set of the
+// pairs written so far; directed graphs are written as they are enumerated.
+func eachEdge(g graph.Graph, opts WriteOptions, f func(e graph.Edge)) {
+	visit := f
+	if opts.Undirected {
+		type pair struct{ from, to string }
+		seen := make(map[pair]struct{})
+		visit = func(e graph.Edge) {
+			p := pair{e.From().String(), e.To().String()}
+			if _, ok := seen[p]; ok {
+				return
+			}
+
+			seen[p] = struct{}{}
+			seen[pair{p.to, p.from}] = struct{}{}
+			f(e)
+		}
+	}
+
+	if it, ok := g.(Iterable); ok {
+		it.EachEdge(visit)
+		return
+	}
+	for _, e := range g.Edges() {
+		visit(e)
+	}
+}
+
+// attributeKeys returns the sorted union of the attribute keys of all nodes.
+func attributeKeys(g graph.Graph, attributes func(graph.Node) map[string]float64) []string {
+	if attributes == nil {
+		return nil
+	}
+
+	set := make(map[string]struct{})
+	eachNode(g, func(n graph.Node) {
+		for key := range attributes(n) {
+			set[key] = struct{}{}
+		}
+	})
+
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedKeys returns the sorted keys of the attributes.
+func sortedKeys(c map[string]float64) []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// hasWeights returns true if any of the edges is weighted.
+func hasWeights(g graph.Graph) bool {
+	weighted := false
+	eachEdge(g, WriteOptions{}, func(e graph.Edge) {
+		if _, ok := e.(graph.WeightedEdge); ok {
+			weighted = true
+		}
+	})
+	return weighted
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,144 @@
+package io
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	goio "io"
+	"strings"
+	"testing"
+
+	"futurae.com/smallworlds/graph"
+	"futurae.com/smallworlds/graph/ring"
+	"futurae.com/smallworlds/world"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestWorld() *world.World {
+	w := world.NewWorld(ring.NewGraph(1, 0.0).WithSeed(42).WithNodes(4).WithShortEdges().WithWeights())
+	w.AddContext(graph.IntNode(0), world.Context{"capacity": 12, "a&b": 1})
+	return w
+}
+
+func Test_WriteGraphML(t *testing.T) {
+	w := newTestWorld()
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteGraphML(&buf, w, WriteOptions{}))
+
+	g, err := ReadGraphML(&buf)
+	assert.NoError(t, err)
+	assert.Len(t, g.Nodes(), 4)
+	assert.Len(t, g.Edges(), 8)
+	assert.Equal(t, g.Attributes(graph.StringNode("0")), map[string]float64{"capacity": 12, "a&b": 1})
+	assert.Equal(t, world.NewWorld(g).WithAttributes(g).Weight(graph.StringNode("0"), graph.StringNode("1")),
+		w.Weight(graph.IntNode(0), graph.IntNode(1)))
+
+	buf.Reset()
+	assert.NoError(t, WriteGraphML(&buf, w, WriteOptions{Undirected: true}))
+	assert.Contains(t, buf.String(), `edgedefault="undirected"`)
+
+	u, err := ReadGraphML(&buf)
+	assert.NoError(t, err)
+	assert.Len(t, u.Edges(), 8)
+}
+
+func Test_WriteDOT(t *testing.T) {
+	g, err := ReadEdgeList(strings.NewReader("a b\nb \"c\"\n"), EdgeListOptions{})
+	assert.NoError(t, err)
+	g.setAttribute("a", "x", 0.5)
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteDOT(&buf, g, WriteOptions{Undirected: true}))
+	assert.Equal(t, `graph {
+  "a" ["x"=0.5];
+  "b";
+  "\"c\"";
+  "a" -- "b";
+  "b" -- "\"c\"";
+}
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(t, WriteDOT(&buf, newTestWorld(), WriteOptions{}))
+	assert.True(t, strings.HasPrefix(buf.String(), "digraph {\n"))
+	assert.Equal(t, 8, strings.Count(buf.String(), "->"))
+	assert.Contains(t, buf.String(), `"0" ["a&b"=1, "capacity"=12];`)
+}
+
+func Test_WriteGEXF(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WriteGEXF(&buf, newTestWorld(), WriteOptions{Undirected: true}))
+
+	var doc struct {
+		Graph struct {
+			EdgeType   string `xml:"defaultedgetype,attr"`
+			Attributes []struct {
+				Title string `xml:"title,attr"`
+			} `xml:"attributes>attribute"`
+			Nodes []struct {
+				ID     string `xml:"id,attr"`
+				Values []struct {
+					Value string `xml:"value,attr"`
+				} `xml:"attvalues>attvalue"`
+			} `xml:"nodes>node"`
+			Edges []struct {
+				Weight string `xml:"weight,attr"`
+			} `xml:"edges>edge"`
+		} `xml:"graph"`
+	}
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, doc.Graph.EdgeType, "undirected")
+	assert.Len(t, doc.Graph.Attributes, 2)
+	assert.Equal(t, doc.Graph.Attributes[0].Title, "a&b")
+	assert.Len(t, doc.Graph.Nodes, 4)
+	assert.Len(t, doc.Graph.Nodes[0].Values, 2)
+	assert.Len(t, doc.Graph.Edges, 4)
+}
+
+func Test_WriteCytoscapeJSON(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WriteCytoscapeJSON(&buf, newTestWorld(), WriteOptions{}))
+
+	var doc struct {
+		Elements struct {
+			Nodes []struct {
+				Data map[string]interface{} `json:"data"`
+			} `json:"nodes"`
+			Edges []struct {
+				Data map[string]interface{} `json:"data"`
+			} `json:"edges"`
+		} `json:"elements"`
+	}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Len(t, doc.Elements.Nodes, 4)
+	assert.Equal(t, doc.Elements.Nodes[0].Data, map[string]interface{}{"id": "0", "capacity": 12.0, "a&b": 1.0})
+	assert.Len(t, doc.Elements.Edges, 8)
+	assert.Equal(t, doc.Elements.Edges[0].Data["id"], "e0")
+}
+
+// collected hides the iterators of a world, thus writers collect its nodes and edges.
+type collected struct {
+	w *world.World
+}
+
+func (c collected) Nodes() []graph.Node { return c.w.Nodes() }
+func (c collected) Edges() []graph.Edge { return c.w.Edges() }
+func (c collected) Attributes(n graph.Node) map[string]float64 {
+	return c.w.Attributes(n)
+}
+
+func Test_Write_Streamed(t *testing.T) {
+	var _ Iterable = (*world.World)(nil)
+	w := newTestWorld()
+
+	writers := []func(goio.Writer, graph.Graph, WriteOptions) error{WriteGraphML, WriteDOT, WriteGEXF, WriteCytoscapeJSON}
+	for _, write := range writers {
+		for _, opts := range []WriteOptions{{}, {Undirected: true}} {
+			var streamed, buf bytes.Buffer
+			assert.NoError(t, write(&streamed, w, opts))
+			assert.NoError(t, write(&buf, collected{w}, opts))
+			assert.Equal(t, buf.String(), streamed.String())
+		}
+	}
+}
@@ -66,6 +66,55 @@ func (w *Graph) WithEdges(n int) *Graph {
 	return w
 }
 
+// WithUndirectedEdges is a builder function that adds
+// _n_ new undirected edges between randomly chosen nodes,
+// i.e. each edge is added in both directions.
+func (w *Graph) WithUndirectedEdges(n int) *Graph {
+	bound := len(w.nodes)
+	added := 0
+
+	for added < n {
+		from := w.rand.Intn(bound)
+		to := w.rand.Intn(bound)
+
+		if (from != to) && !(w.hasEdge(from, to)) {
+			w.addEdge(from, to)
+			w.addEdge(to, from)
+			added++
+		}
+	}
+
+	return w
+}
+
+// WithConnectivity is a builder function that links the graph, so that
+// every node can reach every other node. Every strongly connected component
+// but the largest one is linked to the largest one by an edge in both
+// directions, between randomly chosen nodes.
+func (w *Graph) WithConnectivity() *Graph {
+	components := graph.StronglyConnectedComponents(w)
+
+	largest := 0
+	for i, c := range components {
+		if len(c) > len(components[largest]) {
+			largest = i
+		}
+	}
+
+	for i, c := range components {
+		if i == largest {
+			continue
+		}
+
+		from := int(c[w.rand.Intn(len(c))].(graph.IntNode))
+		to := int(components[largest][w.rand.Intn(len(components[largest]))].(graph.IntNode))
+		w.addEdge(from, to)
+		w.addEdge(to, from)
+	}
+
+	return w
+}
+
 // Nodes exports the internal slice representing nodes
 // to a slice of IntNodes
 func (w *Graph) Nodes() []graph.Node {
@@ -28,3 +28,21 @@ func Test_WithEdges(t *testing.T) {
 	assert.ElementsMatch(t, w.Nodes(), []graph.Node{graph.IntNode(0), graph.IntNode(1), graph.IntNode(2)})
 	assert.Len(t, w.Edges(), 2)
 }
+
+func Test_WithUndirectedEdges(t *testing.T) {
+	w := NewGraph().WithSeed(42).WithNodes(5).WithUndirectedEdges(4)
+
+	assert.Len(t, w.Edges(), 8)
+	for _, e := range w.Edges() {
+		assert.True(t, w.hasEdge(int(e.To().(graph.IntNode)), int(e.From().(graph.IntNode))))
+	}
+}
+
+func Test_WithConnectivity(t *testing.T) {
+	w := NewGraph().WithSeed(42).WithNodes(50).WithEdges(30)
+	assert.Greater(t, len(graph.StronglyConnectedComponents(w)), 1)
+
+	w.WithConnectivity()
+	assert.Len(t, graph.StronglyConnectedComponents(w), 1)
+	assert.Len(t, graph.ConnectedComponents(w), 1)
+}
@@ -12,13 +12,16 @@ import (
 // k and beta parameters. K controls how many short edges
 // within the ring are created, while beta controls how the
 // short edges are rewired.
+//
+// Edges are unweighted unless the graph is built WithWeights.
 type Graph struct {
-	n     int
-	k     int
-	beta  float64
-	nodes []int
-	edges map[int]map[int]struct{}
-	rand  *rand.Rand
+	n        int
+	k        int
+	beta     float64
+	nodes    []int
+	edges    map[int]map[int]struct{}
+	weighted bool
+	rand     *rand.Rand
 }
 
 // NewGraph creates an empty graph with k set to kOver2 * 2.
@@ -41,6 +44,15 @@ func (w *Graph) WithSeed(seed int64) *Graph {
 	return w
 }
 
+// WithWeights is a builder that weighs every edge by the arc length
+// between its ends, i.e. the number of ring steps between them.
+// Rewired edges thus cost more to traverse than short ones.
+func (w *Graph) WithWeights() *Graph {
+	w.weighted = true
+
+	return w
+}
+
 // WithNodes is a builder that adds _n_ new nodes to the graph.
 func (w *Graph) WithNodes(n int) *Graph {
 	w.n = w.n + n
@@ -94,6 +106,33 @@ func (w *Graph) WithDistantEdges() *Graph {
 	return w
 }
 
+// WithConnectivity is a builder that repairs the connectivity of the graph,
+// e.g. after WithDistantEdges, so that every node can reach every other node.
+// Every connected component but the largest one is linked to the largest one
+// by an edge between randomly chosen nodes.
+func (w *Graph) WithConnectivity() *Graph {
+	components := graph.ConnectedComponents(w)
+
+	largest := 0
+	for i, c := range components {
+		if len(c) > len(components[largest]) {
+			largest = i
+		}
+	}
+
+	for i, c := range components {
+		if i == largest {
+			continue
+		}
+
+		p := int(c[w.rand.Intn(len(c))].(graph.IntNode))
+		q := int(components[largest][w.rand.Intn(len(components[largest]))].(graph.IntNode))
+		w.addEdge(p, q)
+	}
+
+	return w
+}
+
 // Nodes exports the ring elements as graph nodes.
 func (w *Graph) Nodes() []graph.Node {
 	ns := make([]graph.Node, 0)
@@ -103,18 +142,36 @@ func (w *Graph) Nodes() []graph.Node {
 	return ns
 }
 
-// Edges exports the edges as the slice of graph edges.
+// Edges exports the edges as the slice of graph edges. If the
+// graph is weighted, the edges are graph.WeightedIntEdges.
 func (w *Graph) Edges() []graph.Edge {
 	es := make([]graph.Edge, 0)
 
 	for from := range w.edges {
 		for to := range w.edges[from] {
-			es = append(es, graph.IntEdge{graph.IntNode(from), graph.IntNode(to)})
+			e := graph.IntEdge{graph.IntNode(from), graph.IntNode(to)}
+			if w.weighted {
+				es = append(es, graph.WeightedIntEdge{IntEdge: e, Cost: float64(w.arcLength(from, to))})
+			} else {
+				es = append(es, e)
+			}
 		}
 	}
 	return es
 }
 
+// arcLength returns the number of ring steps between p and q.
+func (w *Graph) arcLength(p, q int) int {
+	d := p - q
+	if d < 0 {
+		d = -d
+	}
+	if w.n-d < d {
+		return w.n - d
+	}
+	return d
+}
+
 // Valid returns true if n >> k >> ln(n) >> 1.
 // A graph needs to be valid before adding long edges in order to guarantee
 // the small world property.
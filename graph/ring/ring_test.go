@@ -119,3 +119,26 @@ func Test_Valid(t *testing.T) {
 	assert.False(t, w2.Valid()) // n not >> k
 	assert.True(t, w3.Valid())
 }
+
+func Test_WithWeights(t *testing.T) {
+	w := NewGraph(1, 1.0).WithSeed(42).WithNodes(10).WithShortEdges().WithDistantEdges().WithWeights()
+
+	for _, e := range w.Edges() {
+		from := int(e.From().(graph.IntNode))
+		to := int(e.To().(graph.IntNode))
+		assert.Equal(t, float64(w.arcLength(from, to)), graph.Weight(e))
+	}
+
+	assert.Equal(t, 1, w.arcLength(0, 9))
+	assert.Equal(t, 5, w.arcLength(2, 7))
+	assert.Equal(t, 3, w.arcLength(8, 1))
+}
+
+func Test_WithConnectivity(t *testing.T) {
+	w := NewGraph(1, 1.0).WithSeed(42).WithNodes(20).WithShortEdges().WithDistantEdges()
+	assert.Greater(t, len(graph.ConnectedComponents(w)), 1)
+
+	w.WithConnectivity()
+	assert.Len(t, graph.ConnectedComponents(w), 1)
+	assert.Len(t, graph.StronglyConnectedComponents(w), 1)
+}
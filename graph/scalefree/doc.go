@@ -0,0 +1,4 @@
+// Package scalefree implements scale-free graphs grown by preferential
+// attachment, following the Barabási-Albert model and its Holme-Kim
+// variant with tunable clustering.
+package scalefree
@@ -0,0 +1,171 @@
+package scalefree
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"futurae.com/smallworlds/graph"
+)
+
+// Graph holds a graph grown by preferential attachment. Every new node
+// links to m existing nodes, picked with probabilities proportional to
+// their degrees. Thus early nodes grow into hubs, and the degrees follow
+// a power law.
+//
+// With the triad probability p set, every preferential link is followed,
+// with probability p, by a link to a random neighbour of the picked node.
+// This triad formation step closes triangles and raises clustering
+// (Holme and Kim, 2002).
+type Graph struct {
+	m          int
+	p          float64
+	neighbours [][]int
+	edges      map[int]map[int]struct{}
+	ends       []int // every node repeated once per edge end, for preferential picks
+	rand       *rand.Rand
+}
+
+// NewGraph creates an empty graph where every new
+// node links to m existing nodes. It panics if m is less than 1.
+// The core of m nodes is always large enough, since every node after
+// it finds at least m+1 linked nodes to pick from.
+func NewGraph(m int) *Graph {
+	if m < 1 {
+		panic(fmt.Sprintf("Bad number of links %d", m))
+	}
+
+	return &Graph{
+		m:          m,
+		p:          0.0,
+		neighbours: make([][]int, 0),
+		edges:      make(map[int]map[int]struct{}),
+		ends:       make([]int, 0),
+		rand:       rand.New(rand.NewSource(time.Now().UTC().UnixNano())),
+	}
+}
+
+// WithSeed is a builder that sets the random number generator.
+func (w *Graph) WithSeed(seed int64) *Graph {
+	w.rand = rand.New(rand.NewSource(seed))
+
+	return w
+}
+
+// WithTriadProb is a builder that sets the probability of a triad
+// formation step after each preferential link (Holme-Kim model).
+// It applies to nodes added afterwards.
+func (w *Graph) WithTriadProb(p float64) *Graph {
+	w.p = p
+
+	return w
+}
+
+// WithNodes is a builder that grows the graph by _n_ new nodes.
+// The first m nodes form the initial core without edges, and the
+// node that follows links to all of them.
+func (w *Graph) WithNodes(n int) *Graph {
+	for i := 0; i < n; i++ {
+		w.addNode()
+	}
+
+	return w
+}
+
+// Nodes exports the graph nodes as IntNodes.
+func (w *Graph) Nodes() []graph.Node {
+	ns := make([]graph.Node, 0)
+	for k := range w.neighbours {
+		ns = append(ns, graph.IntNode(k))
+	}
+	return ns
+}
+
+// Edges exports the edges, in both directions, as IntEdges.
+func (w *Graph) Edges() []graph.Edge {
+	es := make([]graph.Edge, 0)
+
+	for from, ns := range w.neighbours {
+		for _, to := range ns {
+			es = append(es, graph.IntEdge{graph.IntNode(from), graph.IntNode(to)})
+		}
+	}
+	return es
+}
+
+func (w *Graph) addNode() {
+	v := len(w.neighbours)
+	w.neighbours = append(w.neighbours, make([]int, 0, w.m))
+
+	if v < w.m {
+		return
+	}
+
+	if v == w.m {
+		for u := 0; u < w.m; u++ {
+			w.addEdge(v, u)
+		}
+		return
+	}
+
+	// The ends of v's own edges are excluded from preferential picks,
+	// thus the picks are drawn from the ends as they were before v.
+	ends := len(w.ends)
+
+	for added := 0; added < w.m; {
+		u := w.ends[w.rand.Intn(ends)]
+		if w.hasEdge(v, u) {
+			continue
+		}
+		w.addEdge(v, u)
+		added++
+
+		if added < w.m && w.rand.Float64() < w.p {
+			if t, ok := w.triad(v, u); ok {
+				w.addEdge(v, t)
+				added++
+			}
+		}
+	}
+}
+
+// triad picks a random neighbour of u that v is not yet linked to.
+func (w *Graph) triad(v, u int) (int, bool) {
+	candidates := make([]int, 0)
+	for _, t := range w.neighbours[u] {
+		if t != v && !w.hasEdge(v, t) {
+			candidates = append(candidates, t)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return 0, false
+	}
+	return candidates[w.rand.Intn(len(candidates))], true
+}
+
+func (w *Graph) hasEdge(from int, to int) bool {
+	_, ok := w.edges[from]
+	if !ok {
+		return false
+	}
+
+	_, ok = w.edges[from][to]
+	return ok
+}
+
+func (w *Graph) addEdge(p, q int) {
+	w.addDiEdge(p, q)
+	w.addDiEdge(q, p)
+	w.ends = append(w.ends, p, q)
+}
+
+func (w *Graph) addDiEdge(from int, to int) {
+	_, ok := w.edges[from]
+	if !ok {
+		w.edges[from] = make(map[int]struct{})
+	}
+
+	w.edges[from][to] = struct{}{}
+	w.neighbours[from] = append(w.neighbours[from], to)
+}
@@ -0,0 +1,72 @@
+package scalefree
+
+import (
+	"testing"
+
+	"futurae.com/smallworlds/graph"
+	"github.com/stretchr/testify/assert"
+)
+
+func triangles(w *Graph) int {
+	count := 0
+	for u, ns := range w.neighbours {
+		for _, v := range ns {
+			for _, t := range w.neighbours[v] {
+				if u < v && v < t && w.hasEdge(u, t) {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
+
+func Test_WithNodes(t *testing.T) {
+	w := NewGraph(2).WithSeed(42).WithNodes(3)
+
+	assert.ElementsMatch(t, []graph.Node{graph.IntNode(0), graph.IntNode(1), graph.IntNode(2)}, w.Nodes())
+	assert.ElementsMatch(t, []graph.Edge{
+		graph.IntEdge{graph.IntNode(2), graph.IntNode(0)},
+		graph.IntEdge{graph.IntNode(0), graph.IntNode(2)},
+		graph.IntEdge{graph.IntNode(2), graph.IntNode(1)},
+		graph.IntEdge{graph.IntNode(1), graph.IntNode(2)},
+	}, w.Edges())
+
+	w.WithNodes(7)
+	assert.Len(t, w.Nodes(), 10)
+	assert.Len(t, w.Edges(), 2*2*8)
+}
+
+func Test_NewGraph_BadLinks(t *testing.T) {
+	assert.PanicsWithValue(t, "Bad number of links 0", func() { NewGraph(0) })
+	assert.PanicsWithValue(t, "Bad number of links -1", func() { NewGraph(-1) })
+	assert.Len(t, NewGraph(1).WithSeed(42).WithNodes(5).Edges(), 2*4)
+}
+
+func Test_Degrees(t *testing.T) {
+	w := NewGraph(2).WithSeed(42).WithNodes(2000)
+
+	maxDegree := 0
+	for _, ns := range w.neighbours {
+		assert.GreaterOrEqual(t, len(ns), 2)
+		if len(ns) > maxDegree {
+			maxDegree = len(ns)
+		}
+	}
+	assert.Greater(t, maxDegree, 40) // hubs emerge
+}
+
+func Test_WithTriadProb(t *testing.T) {
+	ba := NewGraph(3).WithSeed(42).WithNodes(1000)
+	hk := NewGraph(3).WithSeed(42).WithTriadProb(0.9).WithNodes(1000)
+
+	assert.Len(t, hk.Edges(), len(ba.Edges()))
+	assert.Greater(t, triangles(hk), 3*triangles(ba))
+}
+
+func Test_WithSeed(t *testing.T) {
+	w1 := NewGraph(3).WithSeed(42).WithTriadProb(0.5).WithNodes(100)
+	w2 := NewGraph(3).WithSeed(42).WithTriadProb(0.5).WithNodes(100)
+
+	assert.Equal(t, w1.Edges(), w2.Edges())
+}
@@ -0,0 +1,10 @@
+package graph
+
+// StringNode is a wrapper around the string type.
+// It provides a Node implementation for graphs with named nodes.
+type StringNode string
+
+// String returns the underlying string.
+func (n StringNode) String() string {
+	return string(n)
+}
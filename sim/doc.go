@@ -0,0 +1,19 @@
+// Package sim runs simulations of agents in a world.
+//
+// A simulation owns a world, a population of agents and a discrete clock.
+// At every tick the scheduler steps each idle agent once along the walk
+// that the agent's policy decides on, either in the order the agents were
+// added or in a randomized order, and then notifies the registered tick
+// functions. Agents share the simulation clock, thus an agent on a walk of
+// several edges is idle again once it has arrived. A simulation runs until
+// one of its stop conditions holds.
+//
+// A simulation may detect encounters, i.e. agents at the same node at the
+// same tick, and notify its encounter functions when agents meet. The
+// encounters form a temporal contact network between the agents, which is
+// a graph.Graph, e.g. to study spreading processes. Encounters may also be
+// computed afterwards from the agents' histories.
+//
+// Tick functions may change the world between ticks, e.g. add and remove
+// edges as roads close, or remove nodes as venues close with RemoveNode.
+package sim
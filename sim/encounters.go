@@ -0,0 +1,379 @@
+package sim
+
+import (
+	"sort"
+
+	"futurae.com/smallworlds/graph"
+	"futurae.com/smallworlds/world"
+)
+
+// Encounter records two agents at the same node during the ticks [Start, End).
+// Agents are identified by their indices in the simulation, and A is less than
+// B. End is -1 while the agents are still together.
+type Encounter struct {
+	A     int
+	B     int
+	Node  graph.Node
+	Start int
+	End   int
+}
+
+// Ongoing returns true if the agents are still together.
+func (e Encounter) Ongoing() bool {
+	return e.End < 0
+}
+
+// EncounterFunc is called when two agents meet, before the tick functions.
+type EncounterFunc func(s *Simulation, e Encounter)
+
+// encounters detects the encounters of a simulation's agents tick by tick.
+type encounters struct {
+	list    []Encounter
+	open    map[[2]int]int
+	cursors []int
+	funcs   []EncounterFunc
+}
+
+// WithEncounters is a builder that detects the encounters of agents, i.e.
+// agents at the same node at the same tick, after the agents are stepped.
+func (s *Simulation) WithEncounters() *Simulation {
+	if s.encounters == nil {
+		s.encounters = &encounters{
+			list:    make([]Encounter, 0),
+			open:    make(map[[2]int]int),
+			cursors: make([]int, 0),
+			funcs:   make([]EncounterFunc, 0),
+		}
+	}
+	return s
+}
+
+// WithEncounterFunc adds a function that is called whenever two agents meet,
+// and detects encounters.
+func (s *Simulation) WithEncounterFunc(f EncounterFunc) *Simulation {
+	s.WithEncounters()
+	s.encounters.funcs = append(s.encounters.funcs, f)
+	return s
+}
+
+// Encounters returns the encounters detected so far, ordered by their
+// start, and by the agents' indices. It is empty unless encounters are
+// detected; see WithEncounters.
+func (s *Simulation) Encounters() []Encounter {
+	if s.encounters == nil {
+		return []Encounter{}
+	}
+
+	es := make([]Encounter, len(s.encounters.list), len(s.encounters.list))
+	copy(es, s.encounters.list)
+	sortEncounters(es)
+	return es
+}
+
+// OngoingEncounters returns the encounters of agents that are together at
+// the current tick, ordered as Encounters. It takes time proportional to the
+// number of ongoing encounters, thus tick functions may call it every tick.
+func (s *Simulation) OngoingEncounters() []Encounter {
+	if s.encounters == nil {
+		return []Encounter{}
+	}
+
+	es := make([]Encounter, 0, len(s.encounters.open))
+	for _, j := range s.encounters.open {
+		es = append(es, s.encounters.list[j])
+	}
+	sortEncounters(es)
+	return es
+}
+
+// ContactNetwork returns the network of the encounters detected so far,
+// in which ongoing encounters last until the current tick.
+func (s *Simulation) ContactNetwork() *ContactNetwork {
+	return newContactNetwork(len(s.Agents), s.Encounters(), s.Clock.Tick())
+}
+
+// detectEncounters groups the agents by their nodes at the current tick,
+// starts the encounters of agents that have just met, and ends the
+// encounters of agents that have parted.
+func (s *Simulation) detectEncounters() {
+	e := s.encounters
+	t := s.Clock.Tick()
+	for len(e.cursors) < len(s.Agents) {
+		e.cursors = append(e.cursors, 0)
+	}
+
+	at := make(map[string][]int)
+	nodes := make([]graph.Node, 0)
+	for i, a := range s.Agents {
+		n, ok := e.nodeAt(i, a, t)
+		if !ok {
+			continue
+		}
+		if _, ok := at[n.String()]; !ok {
+			nodes = append(nodes, n)
+		}
+		at[n.String()] = append(at[n.String()], i)
+	}
+
+	together := make(map[[2]int]bool)
+	for _, n := range nodes {
+		is := at[n.String()]
+		for x := 0; x < len(is); x++ {
+			for y := x + 1; y < len(is); y++ {
+				pair := [2]int{is[x], is[y]}
+				together[pair] = true
+
+				if j, ok := e.open[pair]; ok {
+					if e.list[j].Node.String() == n.String() {
+						continue
+					}
+					e.list[j].End = t // the agents moved on together
+				}
+
+				e.open[pair] = len(e.list)
+				e.list = append(e.list, Encounter{A: is[x], B: is[y], Node: n, Start: t, End: -1})
+				for _, f := range e.funcs {
+					f(s, e.list[len(e.list)-1])
+				}
+			}
+		}
+	}
+
+	for pair, j := range e.open {
+		if !together[pair] {
+			e.list[j].End = t
+			delete(e.open, pair)
+		}
+	}
+}
+
+// nodeAt returns the node of the i-th agent at tick t from its history.
+// Ticks only advance, thus every agent's cursor only moves forward
+// through the agent's events.
+func (e *encounters) nodeAt(i int, a *world.Agent, t int) (graph.Node, bool) {
+	events := a.History.Events
+	c := e.cursors[i]
+	for c < len(events)-1 && events[c].Departed() && events[c].Departure <= t {
+		c++
+	}
+	e.cursors[i] = c
+
+	if c >= len(events) || events[c].Arrival > t || (events[c].Departed() && events[c].Departure <= t) {
+		return nil, false
+	}
+	return events[c].Node, true
+}
+
+// Encounters returns the encounters of the agents from their histories,
+// i.e. the overlaps of their stays at the same nodes, ordered as
+// Simulation.Encounters. Agents are identified by their indices.
+// Encounters are ongoing if both agents have not left the node.
+func Encounters(agents []*world.Agent) []Encounter {
+	type stay struct {
+		agent int
+		event world.Event
+	}
+
+	stays := make(map[string][]stay)
+	nodes := make([]string, 0)
+	for i, a := range agents {
+		for _, ev := range a.History.Events {
+			if ev.Departed() && ev.Dwell() <= 0 {
+				continue
+			}
+			if _, ok := stays[ev.Node.String()]; !ok {
+				nodes = append(nodes, ev.Node.String())
+			}
+			stays[ev.Node.String()] = append(stays[ev.Node.String()], stay{agent: i, event: ev})
+		}
+	}
+
+	es := make([]Encounter, 0)
+	for _, n := range nodes {
+		ss := stays[n]
+		sort.SliceStable(ss, func(i, j int) bool {
+			return ss[i].event.Arrival < ss[j].event.Arrival
+		})
+
+		for i := 0; i < len(ss); i++ {
+			for j := i + 1; j < len(ss); j++ {
+				p, q := ss[i], ss[j]
+				if p.event.Departed() && q.event.Arrival >= p.event.Departure {
+					break
+				}
+				if p.agent == q.agent {
+					continue
+				}
+
+				end := -1
+				switch {
+				case !p.event.Departed():
+					end = q.event.Departure
+				case !q.event.Departed() || p.event.Departure < q.event.Departure:
+					end = p.event.Departure
+				default:
+					end = q.event.Departure
+				}
+
+				a, b := p.agent, q.agent
+				if a > b {
+					a, b = b, a
+				}
+				es = append(es, Encounter{A: a, B: b, Node: p.event.Node, Start: q.event.Arrival, End: end})
+			}
+		}
+	}
+
+	sortEncounters(es)
+	return es
+}
+
+func sortEncounters(es []Encounter) {
+	sort.SliceStable(es, func(i, j int) bool {
+		if es[i].Start != es[j].Start {
+			return es[i].Start < es[j].Start
+		}
+		if es[i].A != es[j].A {
+			return es[i].A < es[j].A
+		}
+		return es[i].B < es[j].B
+	})
+}
+
+// ContactNetwork is the temporal network of the encounters of agents, whose
+// nodes are the agents' indices as graph.IntNodes. Two agents are linked in
+// both directions if they have met.
+type ContactNetwork struct {
+	agents     int
+	encounters []Encounter
+	until      int
+}
+
+// NewContactNetwork creates the contact network of the agents from their
+// histories; see Encounters. Ongoing encounters last until the last tick
+// recorded in the histories.
+func NewContactNetwork(agents []*world.Agent) *ContactNetwork {
+	until := 0
+	for _, a := range agents {
+		for _, ev := range a.History.Events {
+			if ev.Arrival+1 > until {
+				until = ev.Arrival + 1
+			}
+			if ev.Departure > until {
+				until = ev.Departure
+			}
+		}
+	}
+	return newContactNetwork(len(agents), Encounters(agents), until)
+}
+
+func newContactNetwork(agents int, es []Encounter, until int) *ContactNetwork {
+	closed := make([]Encounter, 0, len(es))
+	for _, e := range es {
+		if e.Ongoing() || e.End > until {
+			e.End = until
+		}
+		if e.Start < e.End {
+			closed = append(closed, e)
+		}
+	}
+
+	return &ContactNetwork{
+		agents:     agents,
+		encounters: closed,
+		until:      until,
+	}
+}
+
+// Encounters returns the encounters of the network, which have all ended.
+func (c *ContactNetwork) Encounters() []Encounter {
+	es := make([]Encounter, len(c.encounters), len(c.encounters))
+	copy(es, c.encounters)
+	return es
+}
+
+// Window returns the network of the encounters during the ticks [from, to),
+// which are cut to the window, e.g. to study contacts day by day.
+func (c *ContactNetwork) Window(from, to int) *ContactNetwork {
+	if to > c.until {
+		to = c.until
+	}
+
+	es := make([]Encounter, 0)
+	for _, e := range c.encounters {
+		if e.Start < from {
+			e.Start = from
+		}
+		if e.End > to {
+			e.End = to
+		}
+		if e.Start < e.End {
+			es = append(es, e)
+		}
+	}
+	return &ContactNetwork{agents: c.agents, encounters: es, until: to}
+}
+
+// Nodes returns the agents' indices.
+func (c *ContactNetwork) Nodes() []graph.Node {
+	ns := make([]graph.Node, c.agents, c.agents)
+	for i := range ns {
+		ns[i] = graph.IntNode(i)
+	}
+	return ns
+}
+
+// Edges returns the contacts between agents in both directions,
+// ordered by the agents' indices.
+func (c *ContactNetwork) Edges() []graph.Edge {
+	index := make(map[[2]int]int)
+	contacts := make([]Contact, 0)
+	for _, e := range c.encounters {
+		pair := [2]int{e.A, e.B}
+		i, ok := index[pair]
+		if !ok {
+			i = len(contacts)
+			index[pair] = i
+			contacts = append(contacts, Contact{from: graph.IntNode(e.A), to: graph.IntNode(e.B)})
+		}
+		contacts[i].Encounters++
+		contacts[i].Ticks += e.End - e.Start
+	}
+
+	sort.Slice(contacts, func(i, j int) bool {
+		if contacts[i].from != contacts[j].from {
+			return contacts[i].from < contacts[j].from
+		}
+		return contacts[i].to < contacts[j].to
+	})
+
+	es := make([]graph.Edge, 0, 2*len(contacts))
+	for _, ct := range contacts {
+		back := ct
+		back.from, back.to = ct.to, ct.from
+		es = append(es, ct, back)
+	}
+	return es
+}
+
+// Contact is an edge of a contact network, which sums up the encounters
+// of two agents: their number, and the ticks that the agents spent
+// together. Contacts are unweighted, since weights are costs of
+// traversing edges, while longer contacts are closer.
+type Contact struct {
+	from graph.IntNode
+	to   graph.IntNode
+
+	Encounters int
+	Ticks      int
+}
+
+// From returns the index of the first agent.
+func (c Contact) From() graph.Node {
+	return c.from
+}
+
+// To returns the index of the second agent.
+func (c Contact) To() graph.Node {
+	return c.to
+}
@@ -0,0 +1,99 @@
+package sim
+
+import (
+	"testing"
+
+	"futurae.com/smallworlds/graph"
+	"futurae.com/smallworlds/graph/ring"
+	"futurae.com/smallworlds/world"
+	"github.com/stretchr/testify/assert"
+)
+
+// newMeeting creates a simulation in which agent 1 walks to agent 0,
+// stays with it during the ticks [2, 5), and then walks away.
+func newMeeting() *Simulation {
+	g := ring.NewGraph(1, 0).WithNodes(6).WithShortEdges()
+	w := world.NewWorld(g)
+
+	stay := world.PolicyFunc(func(a *world.Agent, w *world.World, c *world.Clock) (world.Walk, world.Reason) {
+		return world.Walk{a.State}, world.ReasonVisit
+	})
+	meet := world.PolicyFunc(func(a *world.Agent, w *world.World, c *world.Clock) (world.Walk, world.Reason) {
+		if c.Tick() < 4 {
+			return a.Route(graph.IntNode(0)), world.ReasonVisit
+		}
+		return a.Route(graph.IntNode(3)), world.ReasonVisit
+	})
+
+	return NewSimulation(w).WithSeed(42).
+		WithAgent(world.NewAgent(w).WithSeed(1).WithK(1).WithPolicy(stay).WithState(graph.IntNode(0))).
+		WithAgent(world.NewAgent(w).WithSeed(2).WithK(1).WithPolicy(meet).WithState(graph.IntNode(2)))
+}
+
+func Test_Encounters(t *testing.T) {
+	met := make([]Encounter, 0)
+	s := newMeeting().WithEncounterFunc(func(s *Simulation, e Encounter) {
+		met = append(met, e)
+	})
+
+	s.Run(3)
+	assert.Equal(t, []Encounter{{A: 0, B: 1, Node: graph.IntNode(0), Start: 2, End: -1}}, met)
+	assert.True(t, s.Encounters()[0].Ongoing())
+	assert.Equal(t, met, s.OngoingEncounters())
+
+	s.Run(5)
+	meeting := Encounter{A: 0, B: 1, Node: graph.IntNode(0), Start: 2, End: 5}
+	assert.Len(t, met, 1)
+	assert.Equal(t, []Encounter{meeting}, s.Encounters())
+	assert.Equal(t, []Encounter{meeting}, Encounters(s.Agents))
+	assert.Empty(t, s.OngoingEncounters())
+}
+
+func Test_Encounters_Histories(t *testing.T) {
+	s := newTestSimulation(8).WithEncounters()
+	s.Run(50)
+
+	ended := func(es []Encounter) []Encounter {
+		acc := make([]Encounter, 0)
+		for _, e := range es {
+			if !e.Ongoing() && e.End < s.Clock.Tick() {
+				acc = append(acc, e)
+			}
+		}
+		return acc
+	}
+
+	assert.NotEmpty(t, ended(s.Encounters()))
+	assert.Equal(t, ended(Encounters(s.Agents)), ended(s.Encounters()))
+}
+
+func Test_Encounters_Disabled(t *testing.T) {
+	s := newMeeting()
+	s.Run(8)
+
+	assert.Empty(t, s.Encounters())
+	assert.Len(t, Encounters(s.Agents), 1)
+}
+
+func Test_ContactNetwork(t *testing.T) {
+	s := newMeeting().WithEncounters()
+	s.Run(3)
+
+	c := s.ContactNetwork()
+	assert.Equal(t, []graph.Node{graph.IntNode(0), graph.IntNode(1)}, c.Nodes())
+	assert.Equal(t, []graph.Edge{
+		Contact{from: 0, to: 1, Encounters: 1, Ticks: 1},
+		Contact{from: 1, to: 0, Encounters: 1, Ticks: 1},
+	}, c.Edges())
+
+	s.Run(5)
+	c = s.ContactNetwork()
+	assert.Equal(t, 3, c.Edges()[0].(Contact).Ticks)
+	assert.Equal(t, 2, c.Window(3, 10).Edges()[0].(Contact).Ticks)
+	assert.Empty(t, c.Window(5, 10).Edges())
+	assert.Equal(t, c.Edges(), NewContactNetwork(s.Agents).Edges())
+
+	w := world.NewWorld(c)
+	assert.True(t, w.HasEdge(graph.IntNode(0), graph.IntNode(1)))
+	assert.False(t, w.Weighted())
+}
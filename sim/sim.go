@@ -0,0 +1,261 @@
+package sim
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"futurae.com/smallworlds/graph"
+	"futurae.com/smallworlds/world"
+)
+
+// Order defines the order in which agents are stepped within a tick.
+type Order int
+
+const (
+	// Sequential steps agents in the order they were added to the simulation.
+	Sequential Order = iota
+
+	// Shuffled steps agents in a random order that changes every tick.
+	Shuffled
+)
+
+// StopCondition returns true when the simulation should stop.
+type StopCondition func(s *Simulation) bool
+
+// TickFunc is called at the end of every tick, after all agents
+// have been stepped and before the clock advances.
+type TickFunc func(s *Simulation)
+
+// Simulation holds a world, the agents that live in it,
+// and the clock that drives them.
+type Simulation struct {
+	World  *world.World
+	Agents []*world.Agent
+	Clock  *world.Clock
+
+	order     Order
+	workers   int
+	rand      *rand.Rand
+	stops     []StopCondition
+	tickFuncs []TickFunc
+
+	encounters *encounters
+}
+
+// NewSimulation creates a simulation in the given world with no agents.
+// Agents are stepped sequentially, and the simulation runs until it is
+// stopped by its caller. The world's own clock is left as it is, since
+// several simulations may share a world; see Contexts.
+func NewSimulation(w *world.World) *Simulation {
+	clock := world.NewClock()
+
+	return &Simulation{
+		World:     w,
+		Agents:    make([]*world.Agent, 0),
+		Clock:     clock,
+		order:     Sequential,
+		workers:   1,
+		rand:      rand.New(rand.NewSource(time.Now().UTC().UnixNano())),
+		stops:     make([]StopCondition, 0),
+		tickFuncs: make([]TickFunc, 0),
+	}
+}
+
+// WithSeed is a builder that sets the random number generator
+// used by the scheduler.
+func (s *Simulation) WithSeed(seed int64) *Simulation {
+	s.rand = rand.New(rand.NewSource(seed))
+	return s
+}
+
+// WithClock is a builder that sets the simulation clock,
+// and shares it with the simulation's agents.
+func (s *Simulation) WithClock(c *world.Clock) *Simulation {
+	s.Clock = c
+	for _, a := range s.Agents {
+		a.WithClock(c)
+	}
+	return s
+}
+
+// Contexts maps the given walk onto the contexts of its nodes, taking the
+// walk to start at the current tick of the simulation clock; see
+// World.ContextsAt.
+func (s *Simulation) Contexts(walk world.Walk) []world.Context {
+	return s.World.ContextsAt(walk, s.Clock, s.Clock.Tick())
+}
+
+// WithAgent adds the given agent to the simulation. The agent's
+// walks are then timed by the simulation clock.
+func (s *Simulation) WithAgent(a *world.Agent) *Simulation {
+	s.Agents = append(s.Agents, a.WithClock(s.Clock))
+	return s
+}
+
+// WithAgents adds the given agents to the simulation.
+func (s *Simulation) WithAgents(as []*world.Agent) *Simulation {
+	for _, a := range as {
+		s.WithAgent(a)
+	}
+	return s
+}
+
+// WithOrder is a builder that sets the order in which agents are stepped.
+func (s *Simulation) WithOrder(o Order) *Simulation {
+	s.order = o
+	return s
+}
+
+// WithWorkers is a builder that steps agents in parallel on a pool of n
+// workers. Agents draw all random choices from their own generators, thus
+// seeded agents produce identical histories regardless of the number of
+// workers. The world must not be modified while agents are stepped.
+func (s *Simulation) WithWorkers(n int) *Simulation {
+	if n < 1 {
+		n = 1
+	}
+	s.workers = n
+	return s
+}
+
+// WithStopCondition adds a condition that stops the simulation. The
+// conditions are checked before every tick.
+func (s *Simulation) WithStopCondition(c StopCondition) *Simulation {
+	s.stops = append(s.stops, c)
+	return s
+}
+
+// WithTickFunc adds a function that is called at the end of every tick.
+func (s *Simulation) WithTickFunc(f TickFunc) *Simulation {
+	s.tickFuncs = append(s.tickFuncs, f)
+	return s
+}
+
+// RemoveNode removes the node from the world, e.g. when a venue closes,
+// and from the addresses of all agents. Agents at the node move to one of
+// its neighbours, or to any node if it has none, picked with the
+// simulation's random number generator. Walks under way are not changed,
+// thus agents heading to the node arrive there, and then move.
+//
+// The world must not change while agents are stepped, thus nodes and edges
+// are best added and removed by tick functions.
+func (s *Simulation) RemoveNode(n graph.Node) {
+	if !s.World.HasNode(n) {
+		return
+	}
+
+	targets := s.World.Neighbourhood(n)
+	if len(targets) == 0 {
+		for _, m := range s.World.Nodes() {
+			if m.String() != n.String() {
+				targets = append(targets, m)
+			}
+		}
+	}
+
+	for _, a := range s.Agents {
+		a.RemoveAddress(n)
+		if a.State != nil && a.State.String() == n.String() && len(targets) > 0 {
+			a.WithState(targets[s.rand.Intn(len(targets))])
+		}
+	}
+
+	s.World.RemoveNode(n)
+}
+
+// Stopped returns true if any of the stop conditions holds.
+func (s *Simulation) Stopped() bool {
+	for _, stop := range s.stops {
+		if stop(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Step runs a single tick: every idle agent moves once, encounters are
+// detected if enabled, the tick functions are called, and the clock
+// advances. Agents that are still on their way are not stepped.
+func (s *Simulation) Step() {
+	if s.workers > 1 {
+		s.stepParallel()
+	} else {
+		for _, i := range s.schedule() {
+			s.step(s.Agents[i])
+		}
+	}
+
+	if s.encounters != nil {
+		s.detectEncounters()
+	}
+
+	for _, f := range s.tickFuncs {
+		f(s)
+	}
+
+	s.Clock.Advance()
+}
+
+// Run steps the simulation until a stop condition holds, or until
+// maxTicks ticks have been run. It returns the number of ticks run.
+func (s *Simulation) Run(maxTicks int) int {
+	ticks := 0
+	for ticks < maxTicks && !s.Stopped() {
+		s.Step()
+		ticks++
+	}
+	return ticks
+}
+
+func (s *Simulation) step(a *world.Agent) {
+	if a.Idle() {
+		a.Step()
+	}
+}
+
+// stepParallel distributes the scheduled agents over the workers.
+func (s *Simulation) stepParallel() {
+	agents := make(chan *world.Agent)
+	var wg sync.WaitGroup
+
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for a := range agents {
+				s.step(a)
+			}
+		}()
+	}
+
+	for _, i := range s.schedule() {
+		agents <- s.Agents[i]
+	}
+	close(agents)
+	wg.Wait()
+}
+
+// schedule returns the indices of agents in the order
+// they are stepped in the current tick.
+func (s *Simulation) schedule() []int {
+	order := make([]int, len(s.Agents), len(s.Agents))
+	for i := range order {
+		order[i] = i
+	}
+
+	if s.order == Shuffled {
+		s.rand.Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+	}
+	return order
+}
+
+// AfterTicks returns a stop condition that holds once
+// the clock has reached the given tick.
+func AfterTicks(n int) StopCondition {
+	return func(s *Simulation) bool {
+		return s.Clock.Tick() >= n
+	}
+}
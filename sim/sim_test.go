@@ -0,0 +1,194 @@
+package sim
+
+import (
+	"testing"
+	"time"
+
+	"futurae.com/smallworlds/graph"
+	"futurae.com/smallworlds/graph/ring"
+	"futurae.com/smallworlds/world"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSimulation(n int) *Simulation {
+	g := ring.NewGraph(2, 0).WithSeed(42).WithNodes(20).WithShortEdges()
+	w := world.NewWorld(g).WithSeed(42)
+	s := NewSimulation(w).WithSeed(42)
+
+	for i := 0; i < n; i++ {
+		s.WithAgent(world.NewAgent(w).
+			WithSeed(int64(i)).
+			WithState(g.Nodes()[i]).
+			WithMaxExploreLen(2).
+			WithExploreProb(1.0))
+	}
+	return s
+}
+
+func Test_NewSimulation(t *testing.T) {
+	s := newTestSimulation(3)
+
+	assert.Len(t, s.Agents, 3)
+	assert.Equal(t, 0, s.Clock.Tick())
+	assert.False(t, s.Stopped())
+}
+
+func Test_Step(t *testing.T) {
+	s := newTestSimulation(3)
+	ticks := make([]int, 0)
+	s.WithTickFunc(func(s *Simulation) {
+		ticks = append(ticks, s.Clock.Tick())
+	})
+
+	s.Step()
+	s.Step()
+
+	assert.Equal(t, []int{0, 1}, ticks)
+	assert.Equal(t, 2, s.Clock.Tick())
+	for _, a := range s.Agents {
+		assert.Equal(t, 2, a.History.Len())
+		assert.Equal(t, 2, a.Time())
+	}
+}
+
+func Test_Step_Busy(t *testing.T) {
+	s := newTestSimulation(0)
+	nodes := s.World.Nodes()
+	a := world.NewAgent(s.World).
+		WithState(nodes[0]).
+		WithAddress(nodes[0]).
+		WithAddress(nodes[8]).
+		WithVisitDistribution([][]float64{{0, 1}, {1, 0}}).
+		WithK(1).
+		WithExploreProb(0.0)
+	s.WithAgent(a)
+
+	s.Run(3)
+	assert.Equal(t, 1, a.History.Len())
+	assert.Equal(t, nodes[8], a.State)
+
+	s.Run(2)
+	assert.Equal(t, 2, a.History.Len())
+	assert.Equal(t, nodes[0], a.State)
+	assert.Equal(t, 8, a.History.Events[len(a.History.Events)-1].Arrival)
+}
+
+func Test_Run(t *testing.T) {
+	s := newTestSimulation(2).WithStopCondition(AfterTicks(5))
+
+	assert.Equal(t, 5, s.Run(100))
+	assert.True(t, s.Stopped())
+	assert.Equal(t, 0, s.Run(100))
+
+	s = newTestSimulation(2)
+	assert.Equal(t, 7, s.Run(7))
+}
+
+func Test_WithWorkers(t *testing.T) {
+	histories := func(workers int) [][]world.Event {
+		g := ring.NewGraph(3, 0.2).WithSeed(42).WithNodes(200).WithShortEdges().WithDistantEdges()
+		w := world.NewWorld(g)
+		s := NewSimulation(w).WithSeed(42).WithOrder(Shuffled).WithWorkers(workers)
+
+		nodes := g.Nodes()
+		for i := 0; i < 50; i++ {
+			s.WithAgent(world.NewAgent(w).
+				WithSeed(int64(i)).
+				WithState(nodes[i]).
+				WithAddress(nodes[i]).
+				WithAddress(nodes[i+100]).
+				WithAddress(nodes[i+150]).
+				WithVisitDistribution([][]float64{{0.2, 0.5, 0.3}, {0.5, 0.2, 0.3}, {0.3, 0.3, 0.4}}).
+				WithExploreProb(0.3))
+		}
+		s.Run(20)
+
+		hs := make([][]world.Event, 0)
+		for _, a := range s.Agents {
+			hs = append(hs, a.History.Events)
+		}
+		return hs
+	}
+
+	expected := histories(1)
+	assert.Equal(t, expected, histories(1))
+	assert.Equal(t, expected, histories(4))
+	assert.Equal(t, expected, histories(16))
+}
+
+func Test_schedule(t *testing.T) {
+	s := newTestSimulation(5)
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, s.schedule())
+
+	s.WithOrder(Shuffled)
+	assert.ElementsMatch(t, []int{0, 1, 2, 3, 4}, s.schedule())
+	assert.NotEqual(t, s.schedule(), s.schedule())
+}
+
+func Test_Clock(t *testing.T) {
+	start := time.Date(2021, time.March, 1, 8, 0, 0, 0, time.UTC)
+	s := newTestSimulation(1).WithClock(world.NewClock().WithStart(start).WithStep(time.Hour))
+
+	s.Run(3)
+	assert.Equal(t, start.Add(3*time.Hour), s.Clock.Now())
+}
+
+func Test_RemoveNode(t *testing.T) {
+	s := newTestSimulation(3)
+	nodes := s.World.Nodes()
+	for _, a := range s.Agents {
+		a.WithAddresses([]graph.Node{nodes[1], nodes[10]}).
+			WithVisitDistribution([][]float64{{0, 1}, {1, 0}}).
+			WithExploreProb(0)
+	}
+
+	s.WithTickFunc(func(s *Simulation) {
+		if s.Clock.Tick() == 2 {
+			s.RemoveNode(nodes[10])
+		}
+	})
+	s.Run(20)
+
+	assert.False(t, s.World.HasNode(nodes[10]))
+	assert.Len(t, s.World.Nodes(), 19)
+	for _, a := range s.Agents {
+		assert.Equal(t, []graph.Node{nodes[1]}, a.Addresses)
+		assert.NotEqual(t, nodes[10], a.State)
+
+		// walks under way still arrive, and then the agents move away
+		for i, e := range a.History.Events {
+			if e.Node == nodes[10] && e.Arrival > 2 {
+				assert.Equal(t, world.ReasonStart, a.History.Events[i+1].Reason)
+			}
+		}
+	}
+}
+
+func Test_Contexts(t *testing.T) {
+	s := newTestSimulation(0).WithClock(world.NewClock().WithStep(time.Hour))
+	s.World.AddFeature(graph.IntNode(1), "crowd", world.Daily(0.5, 0.5, 1))
+
+	walk := world.Walk{graph.IntNode(0), graph.IntNode(1)}
+	assert.InDelta(t, 1.0, s.Contexts(walk)[1]["crowd"], 1e-9)
+	s.Run(12)
+	assert.InDelta(t, 0.0, s.Contexts(walk)[1]["crowd"], 1e-9)
+
+	other := NewSimulation(s.World).WithClock(world.NewClock().WithStep(time.Hour))
+	assert.InDelta(t, 1.0, other.Contexts(walk)[1]["crowd"], 1e-9)
+	assert.InDelta(t, 0.0, s.Contexts(walk)[1]["crowd"], 1e-9)
+}
+
+func Test_Step_Policy(t *testing.T) {
+	s := newTestSimulation(2)
+	home := graph.IntNode(7)
+	for _, a := range s.Agents {
+		a.WithK(1).WithPolicy(world.PolicyFunc(func(a *world.Agent, w *world.World, c *world.Clock) (world.Walk, world.Reason) {
+			return a.Route(home), world.ReasonVisit
+		}))
+	}
+
+	s.Run(10)
+	for _, a := range s.Agents {
+		assert.Equal(t, home, a.State)
+	}
+}
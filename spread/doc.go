@@ -0,0 +1,14 @@
+// Package spread runs compartmental spreading processes, such as epidemics
+// and rumours, over the encounters of the agents of a simulation.
+//
+// Every agent is in a compartment: susceptible, exposed, infectious or
+// recovered. At every tick an infectious agent infects each susceptible agent
+// it is together with at the same node with a transmission probability, which
+// may depend on the context of the node, e.g. how crowded it is. Infected
+// agents become exposed in SEIR processes, and infectious otherwise. Exposed
+// agents become infectious, and infectious agents recover, or become
+// susceptible again in SIS processes, with fixed probabilities per tick.
+//
+// The process counts the agents in every compartment at every tick, e.g. to
+// compare the spread in ring lattices, small worlds and random graphs.
+package spread
@@ -0,0 +1,240 @@
+package spread
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"futurae.com/smallworlds/sim"
+	"futurae.com/smallworlds/world"
+)
+
+// State is the compartment of an agent.
+type State int
+
+const (
+	// Susceptible agents may be infected.
+	Susceptible State = iota
+	// Exposed agents are infected, but do not infect others yet.
+	Exposed
+	// Infectious agents infect the susceptible agents they meet.
+	Infectious
+	// Recovered agents are immune.
+	Recovered
+)
+
+// String returns the name of the compartment.
+func (s State) String() string {
+	switch s {
+	case Susceptible:
+		return "susceptible"
+	case Exposed:
+		return "exposed"
+	case Infectious:
+		return "infectious"
+	case Recovered:
+		return "recovered"
+	}
+	return "unknown"
+}
+
+// Model selects the compartments that agents pass through.
+type Model int
+
+const (
+	// SIR agents recover once they are no longer infectious.
+	SIR Model = iota
+	// SEIR agents are exposed before they become infectious.
+	SEIR
+	// SIS agents become susceptible again, e.g. rumours that are forgotten.
+	SIS
+)
+
+// Counts is the number of agents in each compartment at a tick.
+type Counts struct {
+	Tick        int
+	Susceptible int
+	Exposed     int
+	Infectious  int
+	Recovered   int
+}
+
+// String returns the counts as a line of a table.
+func (c Counts) String() string {
+	return fmt.Sprintf("%d\tS=%d\tE=%d\tI=%d\tR=%d", c.Tick, c.Susceptible, c.Exposed, c.Infectious, c.Recovered)
+}
+
+// Process is a spreading process over the encounters of a simulation's
+// agents, which are identified by their indices in the simulation.
+type Process struct {
+	model        Model
+	transmission float64
+	incubation   float64
+	recovery     float64
+	context      func(c world.Context) float64
+
+	states []State
+	counts []Counts
+	rand   *rand.Rand
+}
+
+// NewProcess creates a process of the given model in which infectious agents
+// infect the susceptible agents they are together with with the transmission
+// probability per tick, and stop being infectious with the recovery
+// probability per tick. All agents are susceptible.
+func NewProcess(m Model, transmission, recovery float64) *Process {
+	return &Process{
+		model:        m,
+		transmission: transmission,
+		incubation:   1,
+		recovery:     recovery,
+		states:       make([]State, 0),
+		counts:       make([]Counts, 0),
+		rand:         rand.New(rand.NewSource(time.Now().UTC().UnixNano())),
+	}
+}
+
+// WithSeed is a builder that sets the random number generator.
+func (p *Process) WithSeed(seed int64) *Process {
+	p.rand = rand.New(rand.NewSource(seed))
+	return p
+}
+
+// WithIncubation is a builder that sets the probability per tick that
+// exposed agents of SEIR processes become infectious. By default exposed
+// agents become infectious after one tick.
+func (p *Process) WithIncubation(prob float64) *Process {
+	p.incubation = prob
+	return p
+}
+
+// WithContext is a builder that scales the transmission probability at each
+// node by a function of the node's context at the current tick, e.g. to
+// spread faster at crowded nodes. The scaled probability is capped at 1.
+func (p *Process) WithContext(f func(c world.Context) float64) *Process {
+	p.context = f
+	return p
+}
+
+// Infect makes the agents with the given indices infectious,
+// e.g. to seed the process before the simulation runs.
+func (p *Process) Infect(agents ...int) *Process {
+	for _, i := range agents {
+		p.grow(i + 1)
+		p.states[i] = Infectious
+	}
+	return p
+}
+
+// Attach detects the encounters of the simulation's agents, and advances
+// the process in a tick function after the encounters of every tick.
+func (p *Process) Attach(s *sim.Simulation) *Process {
+	s.WithEncounters().WithTickFunc(p.step)
+	return p
+}
+
+// State returns the compartment of the agent with the given index.
+func (p *Process) State(agent int) State {
+	if agent >= len(p.states) {
+		return Susceptible
+	}
+	return p.states[agent]
+}
+
+// Counts returns the number of agents in each compartment
+// at the end of every tick that the process ran.
+func (p *Process) Counts() []Counts {
+	cs := make([]Counts, len(p.counts), len(p.counts))
+	copy(cs, p.counts)
+	return cs
+}
+
+// Extinct returns a stop condition that holds once the process has run,
+// and no agent is exposed or infectious.
+func (p *Process) Extinct() sim.StopCondition {
+	return func(s *sim.Simulation) bool {
+		if len(p.counts) == 0 {
+			return false
+		}
+		last := p.counts[len(p.counts)-1]
+		return last.Exposed == 0 && last.Infectious == 0
+	}
+}
+
+// grow makes the agents up to n known to the process, as susceptible.
+func (p *Process) grow(n int) {
+	for len(p.states) < n {
+		p.states = append(p.states, Susceptible)
+	}
+}
+
+// step advances the process by a tick. All agents change their
+// compartments at once, by the compartments at the start of the tick.
+func (p *Process) step(s *sim.Simulation) {
+	p.grow(len(s.Agents))
+	next := make([]State, len(p.states), len(p.states))
+	copy(next, p.states)
+
+	for _, e := range s.OngoingEncounters() {
+		from, to := e.A, e.B
+		if p.states[to] == Infectious {
+			from, to = to, from
+		}
+		if p.states[from] != Infectious || p.states[to] != Susceptible || next[to] != Susceptible {
+			continue
+		}
+
+		if p.rand.Float64() < p.transmissionAt(s, e) {
+			next[to] = Infectious
+			if p.model == SEIR {
+				next[to] = Exposed
+			}
+		}
+	}
+
+	for i, st := range p.states {
+		switch {
+		case st == Exposed && p.rand.Float64() < p.incubation:
+			next[i] = Infectious
+		case st == Infectious && p.rand.Float64() < p.recovery:
+			next[i] = Recovered
+			if p.model == SIS {
+				next[i] = Susceptible
+			}
+		}
+	}
+
+	p.states = next
+	p.counts = append(p.counts, p.count(s.Clock.Tick()))
+}
+
+// transmissionAt returns the transmission probability at the node of the
+// encounter.
+func (p *Process) transmissionAt(s *sim.Simulation, e sim.Encounter) float64 {
+	if p.context == nil {
+		return p.transmission
+	}
+
+	prob := p.transmission * p.context(s.World.ContextAt(e.Node, s.Clock.Now()))
+	if prob > 1 {
+		return 1
+	}
+	return prob
+}
+
+func (p *Process) count(tick int) Counts {
+	c := Counts{Tick: tick}
+	for _, st := range p.states {
+		switch st {
+		case Susceptible:
+			c.Susceptible++
+		case Exposed:
+			c.Exposed++
+		case Infectious:
+			c.Infectious++
+		case Recovered:
+			c.Recovered++
+		}
+	}
+	return c
+}
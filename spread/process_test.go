@@ -0,0 +1,93 @@
+package spread
+
+import (
+	"testing"
+
+	"futurae.com/smallworlds/graph"
+	"futurae.com/smallworlds/graph/ring"
+	"futurae.com/smallworlds/sim"
+	"futurae.com/smallworlds/world"
+	"github.com/stretchr/testify/assert"
+)
+
+// gathering creates a simulation of n agents that stay at node 0.
+func gathering(n int) *sim.Simulation {
+	w := world.NewWorld(ring.NewGraph(1, 0).WithNodes(4).WithShortEdges())
+	stay := world.PolicyFunc(func(a *world.Agent, w *world.World, c *world.Clock) (world.Walk, world.Reason) {
+		return world.Walk{a.State}, world.ReasonVisit
+	})
+
+	s := sim.NewSimulation(w).WithSeed(42)
+	for i := 0; i < n; i++ {
+		s.WithAgent(world.NewAgent(w).WithSeed(int64(i)).WithPolicy(stay).WithState(graph.IntNode(0)))
+	}
+	return s
+}
+
+func Test_SIR(t *testing.T) {
+	s := gathering(3)
+	p := NewProcess(SIR, 1, 0).WithSeed(42).Infect(0).Attach(s)
+
+	s.Run(1)
+	assert.Equal(t, []Counts{{Tick: 0, Infectious: 3}}, p.Counts())
+	assert.Equal(t, Infectious, p.State(2))
+
+	s = gathering(3)
+	p = NewProcess(SIR, 1, 1).WithSeed(42).Infect(0).Attach(s)
+	s.WithStopCondition(p.Extinct()).Run(10)
+	assert.Equal(t, []Counts{{Tick: 0, Infectious: 2, Recovered: 1}, {Tick: 1, Recovered: 3}}, p.Counts())
+}
+
+func Test_SEIR(t *testing.T) {
+	s := gathering(2)
+	p := NewProcess(SEIR, 1, 0).WithSeed(42).Infect(0).Attach(s)
+
+	s.Run(2)
+	assert.Equal(t, []Counts{{Tick: 0, Exposed: 1, Infectious: 1}, {Tick: 1, Infectious: 2}}, p.Counts())
+}
+
+func Test_SIS(t *testing.T) {
+	s := gathering(2)
+	p := NewProcess(SIS, 1, 1).WithSeed(42).Infect(0).Attach(s)
+
+	s.Run(2)
+	assert.Equal(t, []Counts{{Tick: 0, Susceptible: 1, Infectious: 1}, {Tick: 1, Susceptible: 1, Infectious: 1}}, p.Counts())
+	assert.Equal(t, Susceptible, p.State(1))
+}
+
+func Test_Process_Context(t *testing.T) {
+	crowded := func(c world.Context) float64 { return c["crowded"] }
+
+	s := gathering(2)
+	p := NewProcess(SIR, 1, 0).WithContext(crowded).Infect(0).Attach(s)
+	s.Run(5)
+	assert.Equal(t, Susceptible, p.State(1))
+
+	s = gathering(2)
+	s.World.AddFeature(graph.IntNode(0), "crowded", world.NewScheduledFeature(0).WithValue(world.Hours(0, 24), 2))
+	p = NewProcess(SIR, 0.5, 0).WithContext(crowded).Infect(0).Attach(s)
+	s.Run(1)
+	assert.Equal(t, Infectious, p.State(1))
+}
+
+func Test_Process_Ring(t *testing.T) {
+	g := ring.NewGraph(2, 0.1).WithSeed(42).WithNodes(30).WithShortEdges()
+	w := world.NewWorld(g).WithSeed(42)
+	s := sim.NewSimulation(w).WithSeed(42)
+	for i := 0; i < 60; i++ {
+		s.WithAgent(world.NewAgent(w).WithSeed(int64(i)).WithExploreProb(1).WithState(g.Nodes()[i%30]))
+	}
+	p := NewProcess(SIR, 0.5, 0.05).WithSeed(42).Infect(0).Attach(s)
+	s.WithStopCondition(p.Extinct()).Run(2000)
+
+	cs := p.Counts()
+	last := cs[len(cs)-1]
+	assert.Equal(t, 0, last.Infectious)
+	assert.True(t, last.Recovered > 1)
+	for i, c := range cs {
+		assert.Equal(t, 60, c.Susceptible+c.Exposed+c.Infectious+c.Recovered)
+		if i > 0 {
+			assert.True(t, c.Recovered >= cs[i-1].Recovered)
+		}
+	}
+}
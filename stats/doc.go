@@ -1,4 +1,5 @@
 // Package stats provides functions to create
-// transition matrices between _n_ points.
+// transition matrices between _n_ points, and seeded
+// samplers that draw from discrete distributions.
 
 package stats
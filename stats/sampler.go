@@ -0,0 +1,130 @@
+package stats
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Sampler draws positions from a discrete distribution. Samplers do not
+// hold a random number generator, thus a single sampler may be shared
+// by agents that each draw from their own seeded generator.
+type Sampler interface {
+	Sample(r *rand.Rand) int
+	Len() int
+}
+
+// CumulativeSampler draws by scanning the cumulative distribution.
+// It takes O(n) time per draw, and no setup.
+type CumulativeSampler []float64
+
+// NewCumulativeSampler creates a sampler over the given distribution.
+// The distribution does not need to be normalized. It panics on the
+// same distributions as NewAliasSampler.
+func NewCumulativeSampler(dist []float64) CumulativeSampler {
+	sum := checkDistribution(dist)
+
+	s := make(CumulativeSampler, len(dist), len(dist))
+	for i, p := range dist {
+		s[i] = p / sum
+	}
+	return s
+}
+
+// Sample draws a position of the distribution.
+func (s CumulativeSampler) Sample(r *rand.Rand) int {
+	return pick(r.Float64(), s)
+}
+
+// Len returns the size of the distribution.
+func (s CumulativeSampler) Len() int {
+	return len(s)
+}
+
+// AliasSampler draws with Vose's alias method. It takes O(n) time
+// to set up, and O(1) time per draw, which pays off for large
+// distributions that are drawn from many times.
+type AliasSampler struct {
+	prob  []float64
+	alias []int
+}
+
+// NewAliasSampler creates a sampler over the given distribution.
+// The distribution does not need to be normalized. It panics if the
+// distribution is empty, has negative entries or sums to 0, since
+// there is nothing to draw from.
+func NewAliasSampler(dist []float64) *AliasSampler {
+	n := len(dist)
+	s := &AliasSampler{
+		prob:  make([]float64, n, n),
+		alias: make([]int, n, n),
+	}
+
+	sum := checkDistribution(dist)
+	scaled := make([]float64, n, n)
+	small := make([]int, 0, n)
+	large := make([]int, 0, n)
+
+	for i, p := range dist {
+		scaled[i] = p * float64(n) / sum
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	for len(small) > 0 && len(large) > 0 {
+		l := small[len(small)-1]
+		small = small[:len(small)-1]
+		g := large[len(large)-1]
+		large = large[:len(large)-1]
+
+		s.prob[l] = scaled[l]
+		s.alias[l] = g
+
+		scaled[g] = scaled[g] + scaled[l] - 1
+		if scaled[g] < 1 {
+			small = append(small, g)
+		} else {
+			large = append(large, g)
+		}
+	}
+
+	// Leftovers are due to rounding errors, and are drawn with certainty.
+	for _, i := range append(small, large...) {
+		s.prob[i] = 1
+		s.alias[i] = i
+	}
+
+	return s
+}
+
+// Sample draws a position of the distribution.
+func (s *AliasSampler) Sample(r *rand.Rand) int {
+	i := r.Intn(len(s.prob))
+	if r.Float64() < s.prob[i] {
+		return i
+	}
+	return s.alias[i]
+}
+
+// Len returns the size of the distribution.
+func (s *AliasSampler) Len() int {
+	return len(s.prob)
+}
+
+// checkDistribution returns the sum of the distribution, and panics if
+// the distribution is empty, has negative entries or sums to 0.
+func checkDistribution(dist []float64) float64 {
+	sum := 0.0
+	for _, p := range dist {
+		if p < 0 {
+			panic(fmt.Sprintf("Bad distribution"))
+		}
+		sum += p
+	}
+	if !(sum > 0) {
+		panic(fmt.Sprintf("Bad distribution"))
+	}
+	return sum
+}
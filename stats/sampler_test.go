@@ -0,0 +1,75 @@
+package stats
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func frequencies(s Sampler, r *rand.Rand, draws int) []float64 {
+	fs := make([]float64, s.Len())
+	for i := 0; i < draws; i++ {
+		fs[s.Sample(r)]++
+	}
+	for i := range fs {
+		fs[i] = fs[i] / float64(draws)
+	}
+	return fs
+}
+
+func Test_AliasSampler(t *testing.T) {
+	dist := []float64{0.1, 0.0, 0.6, 0.3}
+	s := NewAliasSampler(dist)
+
+	assert.Equal(t, 4, s.Len())
+	assert.InDeltaSlice(t, dist, frequencies(s, rand.New(rand.NewSource(42)), 100000), 0.01)
+}
+
+func Test_AliasSampler_Unnormalized(t *testing.T) {
+	s := NewAliasSampler([]float64{1, 3})
+
+	assert.InDeltaSlice(t, []float64{0.25, 0.75}, frequencies(s, rand.New(rand.NewSource(42)), 100000), 0.01)
+	assert.Equal(t, 0, NewAliasSampler([]float64{1, 0}).Sample(rand.New(rand.NewSource(42))))
+}
+
+func Test_AliasSampler_Bad(t *testing.T) {
+	assert.Panics(t, func() { NewAliasSampler([]float64{}) })
+	assert.Panics(t, func() { NewAliasSampler([]float64{0, 0}) })
+	assert.Panics(t, func() { NewAliasSampler([]float64{1, -1}) })
+}
+
+func Test_CumulativeSampler(t *testing.T) {
+	dist := []float64{0.5, 0.25, 0.25}
+	s := NewCumulativeSampler(dist)
+
+	assert.InDeltaSlice(t, dist, frequencies(s, rand.New(rand.NewSource(42)), 100000), 0.01)
+
+	s = NewCumulativeSampler([]float64{1, 3})
+	assert.InDeltaSlice(t, []float64{0.25, 0.75}, frequencies(s, rand.New(rand.NewSource(42)), 100000), 0.01)
+}
+
+func Test_CumulativeSampler_Bad(t *testing.T) {
+	assert.PanicsWithValue(t, "Bad distribution", func() { NewCumulativeSampler([]float64{}) })
+	assert.PanicsWithValue(t, "Bad distribution", func() { NewCumulativeSampler([]float64{0, 0}) })
+	assert.PanicsWithValue(t, "Bad distribution", func() { NewCumulativeSampler([]float64{1, -1}) })
+}
+
+func Test_Sampler_Seeded(t *testing.T) {
+	dist := []float64{0.2, 0.2, 0.2, 0.2, 0.2}
+
+	for _, s := range []Sampler{NewAliasSampler(dist), NewCumulativeSampler(dist)} {
+		r1 := rand.New(rand.NewSource(7))
+		r2 := rand.New(rand.NewSource(7))
+		for i := 0; i < 100; i++ {
+			assert.Equal(t, s.Sample(r1), s.Sample(r2))
+		}
+	}
+}
+
+func Test_PickFromDiscreteDistWith(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	assert.Equal(t, 1, PickFromDiscreteDistWith(r, []float64{0, 1}))
+	assert.Equal(t, 0, PickFromDiscreteDistWith(r, []float64{1, 0}))
+}
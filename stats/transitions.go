@@ -53,9 +53,20 @@ func Softmax(array []float64) []float64 {
 }
 
 // PickFromDiscreteDist selects the position of the dist array
-// given the value of the position.
+// given the value of the position. It draws from the global random
+// source; see PickFromDiscreteDistWith and Sampler for reproducible draws.
 func PickFromDiscreteDist(dist []float64) int {
-	r := rand.Float64()
+	return pick(rand.Float64(), dist)
+}
+
+// PickFromDiscreteDistWith selects the position of the dist array
+// given the value of the position, drawing from the given random
+// number generator.
+func PickFromDiscreteDistWith(r *rand.Rand, dist []float64) int {
+	return pick(r.Float64(), dist)
+}
+
+func pick(r float64, dist []float64) int {
 	sum := 0.0
 	for i, prob := range dist {
 		sum += prob
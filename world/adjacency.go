@@ -0,0 +1,242 @@
+package world
+
+import "sort"
+
+// adjacency is the internal edge representation of a world. Nodes
+// are the integers [0, n), and edges are directed and weighted.
+// Unweighted edges have the weight 1.
+//
+// The returned neighbourhoods are read-only views into the representation
+// and must not be modified by the caller.
+type adjacency interface {
+	hasEdge(from, to int) bool
+	weight(from, to int) float64
+	neighbourhood(n int) []int
+	addEdge(from, to int, weight float64)
+	removeEdge(from, to int)
+	resize(n int) adjacency
+}
+
+// adjacencyList stores, for every node, the sorted list of its neighbours.
+// Memory grows with the number of edges rather than the number of node pairs,
+// and an edge lookup is a binary search within a single neighbourhood.
+//
+// Edge weights are kept in lists parallel to the neighbourhoods, which are
+// only allocated once the first edge with a weight other than 1 is added.
+type adjacencyList struct {
+	nodes   [][]int
+	weights [][]float64
+}
+
+func newAdjacencyList(n int) *adjacencyList {
+	return &adjacencyList{nodes: make([][]int, n, n)}
+}
+
+// compact sorts every neighbourhood and removes duplicate edges, keeping
+// the weight of the last duplicate. It is used after bulk insertions
+// with appendEdge.
+func (l *adjacencyList) compact() {
+	for i := range l.nodes {
+		sort.Stable(neighbours{l, i})
+
+		ns := l.nodes[i]
+		k := 0
+		for j := 0; j < len(ns); j++ {
+			if k > 0 && ns[j] == ns[k-1] {
+				k--
+			}
+			ns[k] = ns[j]
+			if l.weights != nil {
+				l.weights[i][k] = l.weights[i][j]
+			}
+			k++
+		}
+		l.nodes[i] = ns[:k:k]
+		if l.weights != nil {
+			l.weights[i] = l.weights[i][:k:k]
+		}
+	}
+}
+
+// appendEdge adds an edge without keeping the neighbourhood sorted.
+// Bulk insertions must be followed by compact.
+func (l *adjacencyList) appendEdge(from, to int, weight float64) {
+	if weight != 1 {
+		l.weigh()
+	}
+	l.nodes[from] = append(l.nodes[from], to)
+	if l.weights != nil {
+		l.weights[from] = append(l.weights[from], weight)
+	}
+}
+
+// weigh allocates the weight lists, with every existing edge weighing 1.
+func (l *adjacencyList) weigh() {
+	if l.weights != nil {
+		return
+	}
+
+	l.weights = make([][]float64, len(l.nodes), len(l.nodes))
+	for i, ns := range l.nodes {
+		l.weights[i] = make([]float64, len(ns), len(ns))
+		for j := range ns {
+			l.weights[i][j] = 1
+		}
+	}
+}
+
+func (l *adjacencyList) search(from, to int) (int, bool) {
+	ns := l.nodes[from]
+	i := sort.SearchInts(ns, to)
+	return i, i < len(ns) && ns[i] == to
+}
+
+func (l *adjacencyList) hasEdge(from, to int) bool {
+	_, ok := l.search(from, to)
+	return ok
+}
+
+func (l *adjacencyList) weight(from, to int) float64 {
+	i, ok := l.search(from, to)
+	if !ok || l.weights == nil {
+		return 1
+	}
+	return l.weights[from][i]
+}
+
+func (l *adjacencyList) neighbourhood(n int) []int {
+	ns := l.nodes[n]
+	return ns[:len(ns):len(ns)]
+}
+
+func (l *adjacencyList) addEdge(from, to int, weight float64) {
+	if weight != 1 {
+		l.weigh()
+	}
+
+	i, ok := l.search(from, to)
+	if !ok {
+		ns := append(l.nodes[from], 0)
+		copy(ns[i+1:], ns[i:])
+		ns[i] = to
+		l.nodes[from] = ns
+
+		if l.weights != nil {
+			ws := append(l.weights[from], 0)
+			copy(ws[i+1:], ws[i:])
+			l.weights[from] = ws
+		}
+	}
+
+	if l.weights != nil {
+		l.weights[from][i] = weight
+	}
+}
+
+func (l *adjacencyList) removeEdge(from, to int) {
+	i, ok := l.search(from, to)
+	if !ok {
+		return
+	}
+
+	ns := l.nodes[from]
+	copy(ns[i:], ns[i+1:])
+	l.nodes[from] = ns[:len(ns)-1]
+
+	if l.weights != nil {
+		ws := l.weights[from]
+		copy(ws[i:], ws[i+1:])
+		l.weights[from] = ws[:len(ws)-1]
+	}
+}
+
+// resize drops the neighbourhoods of the nodes [n, len), or adds
+// empty neighbourhoods for new nodes.
+func (l *adjacencyList) resize(n int) adjacency {
+	for len(l.nodes) < n {
+		l.nodes = append(l.nodes, nil)
+		if l.weights != nil {
+			l.weights = append(l.weights, nil)
+		}
+	}
+	l.nodes = l.nodes[:n]
+	if l.weights != nil {
+		l.weights = l.weights[:n]
+	}
+	return l
+}
+
+// neighbours sorts a single neighbourhood of the list
+// together with its weights.
+type neighbours struct {
+	l *adjacencyList
+	n int
+}
+
+func (ns neighbours) Len() int {
+	return len(ns.l.nodes[ns.n])
+}
+
+func (ns neighbours) Less(i, j int) bool {
+	return ns.l.nodes[ns.n][i] < ns.l.nodes[ns.n][j]
+}
+
+func (ns neighbours) Swap(i, j int) {
+	row := ns.l.nodes[ns.n]
+	row[i], row[j] = row[j], row[i]
+
+	if ns.l.weights != nil {
+		ws := ns.l.weights[ns.n]
+		ws[i], ws[j] = ws[j], ws[i]
+	}
+}
+
+// adjacencyMatrix is the n×n matrix of edge weights, where 0 marks
+// a missing edge. It trades quadratic memory for constant-time edge
+// lookups, and is only sensible for tiny dense graphs.
+type adjacencyMatrix [][]float64
+
+func newAdjacencyMatrix(n int) adjacencyMatrix {
+	array := make(adjacencyMatrix, n, n)
+	for i := 0; i < n; i++ {
+		array[i] = make([]float64, n, n)
+	}
+	return array
+}
+
+func (a adjacencyMatrix) hasEdge(from, to int) bool {
+	return a[from][to] != 0
+}
+
+func (a adjacencyMatrix) weight(from, to int) float64 {
+	return a[from][to]
+}
+
+func (a adjacencyMatrix) neighbourhood(n int) []int {
+	ns := make([]int, 0, 0)
+	for i, p := range a[n] {
+		if p != 0 {
+			ns = append(ns, i)
+		}
+	}
+
+	return ns
+}
+
+func (a adjacencyMatrix) addEdge(from, to int, weight float64) {
+	a[from][to] = weight
+}
+
+func (a adjacencyMatrix) removeEdge(from, to int) {
+	a[from][to] = 0
+}
+
+// resize returns a copy of the matrix over n nodes,
+// dropping or adding rows and columns at the end.
+func (a adjacencyMatrix) resize(n int) adjacency {
+	b := newAdjacencyMatrix(n)
+	for i := 0; i < n && i < len(a); i++ {
+		copy(b[i], a[i])
+	}
+	return b
+}
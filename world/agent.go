@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"futurae.com/smallworlds/graph"
-	"futurae.com/smallworlds/stats"
 )
 
 // Agent type defines a single agent within a world.
@@ -16,20 +15,40 @@ import (
 // An agent explores the world (i.e. takes random walks) with _exploreProb_ probability when
 // given a chance.
 //
-// All walks are recorded in the agent's history as well as the last node that he visited.
+// An agent with preferences over context features, e.g. to avoid rain, is biased
+// towards nodes it prefers when exploring and when choosing between routes.
+//
+// An agent's policy decides where it goes when it steps; by default the agent
+// follows its routine between its addresses, see DefaultPolicy.
+//
+// Every random choice of an agent is drawn from its own random number generator,
+// thus a seeded agent takes the same walks regardless of other agents.
+//
+// All walks are recorded in the agent's history as timestamped events, as well as
+// the last node that he visited. Each edge of a walk takes the agent one tick. An
+// agent without a clock keeps time on its own, while an agent sharing a simulation
+// clock starts a walk no earlier than the clock's current tick.
 type Agent struct {
 	Addresses   []graph.Node
 	Transitions map[graph.Node]map[graph.Node]float64
+	Schedule    *Schedule
 
 	world   *World
 	State   graph.Node
-	History []Walk
+	History History
 
-	rand *rand.Rand
+	rand  *rand.Rand
+	clock *Clock
+	time  int
 
 	maxExploreLen int
 	exploreProb   float64
 	k             int
+
+	preferences    Preferences
+	preferenceKeys []string
+	mobility       Mobility
+	policy         Policy
 }
 
 // NewAgent initializes an agent in the given world.
@@ -40,8 +59,9 @@ func NewAgent(w *World) *Agent {
 		world:       w,
 		Addresses:   make([]graph.Node, 0),
 		Transitions: make(map[graph.Node]map[graph.Node]float64),
-		History:     make([]Walk, 0, 0),
+		History:     NewHistory(),
 		rand:        rand.New(rand.NewSource(time.Now().UTC().UnixNano())),
+		policy:      DefaultPolicy{},
 
 		maxExploreLen: 4,
 		exploreProb:   0.3,
@@ -55,6 +75,23 @@ func (a *Agent) WithSeed(seed int64) *Agent {
 	return a
 }
 
+// WithClock sets the clock that times the agent's walks.
+func (a *Agent) WithClock(c *Clock) *Agent {
+	a.clock = c
+	return a
+}
+
+// World returns the world in which the agent lives.
+func (a *Agent) World() *World {
+	return a.world
+}
+
+// Rand returns the agent's random number generator, from which
+// policies and mobility models draw to keep seeded agents reproducible.
+func (a *Agent) Rand() *rand.Rand {
+	return a.rand
+}
+
 // WithK sets the maximum number of shortest routes that an agent chooses
 // from when traversing between its addresses.
 func (a *Agent) WithK(k int) *Agent {
@@ -76,21 +113,92 @@ func (a *Agent) WithExploreProb(f float64) *Agent {
 }
 
 // WithAddresses adds the given addresses to the agent's addresses.
+// It panics if an address is unreachable; see WithAddress.
 func (a *Agent) WithAddresses(as []graph.Node) *Agent {
 	for _, i := range as {
-		a.Addresses = append(a.Addresses, i)
-		a.Transitions[i] = make(map[graph.Node]float64)
+		a.WithAddress(i)
 	}
 	return a
 }
 
-// WithAddress adds the given address to the agent.
+// WithAddress adds the given address to the agent. It panics if the address
+// is not a node of the world, or if the agent's addresses cannot all reach
+// each other with it, i.e. they must lie in the same strongly connected
+// component; see World.LargestComponent. It also panics if the agent
+// has a schedule, whose matrices cannot cover the new address.
 func (a *Agent) WithAddress(ad graph.Node) *Agent {
+	if a.Schedule != nil {
+		checkSchedule(a.Schedule, len(a.Addresses)+1)
+	}
+	if !a.world.HasNode(ad) {
+		panic(fmt.Sprintf("Unknown address %s", ad))
+	}
+	if len(a.Addresses) > 0 {
+		if !a.world.sameComponent(a.Addresses[0], ad) {
+			panic(fmt.Sprintf("Unreachable address %s", ad))
+		}
+	}
+
 	a.Addresses = append(a.Addresses, ad)
 	a.Transitions[ad] = make(map[graph.Node]float64)
 	return a
 }
 
+// RemoveAddress removes the address from the agent, e.g. when its node
+// is removed from the world. The probabilities of visiting the remaining
+// addresses, in the agent's visit distribution and schedule, are scaled to
+// sum to 1 again, and are uniform if they were all zero. The schedule
+// is copied, since it may be shared with other agents.
+func (a *Agent) RemoveAddress(ad graph.Node) {
+	i := -1
+	for j, n := range a.Addresses {
+		if n.String() == ad.String() {
+			i = j
+		}
+	}
+	if i < 0 {
+		return
+	}
+	a.Addresses = append(a.Addresses[:i:i], a.Addresses[i+1:]...)
+
+	for from, ps := range a.Transitions {
+		if from.String() == ad.String() {
+			delete(a.Transitions, from)
+			continue
+		}
+		for to := range ps {
+			if to.String() == ad.String() {
+				delete(ps, to)
+			}
+		}
+		normalize(ps, a.Addresses)
+	}
+
+	if a.Schedule != nil {
+		a.Schedule = a.Schedule.without(i)
+	}
+}
+
+// normalize scales the probabilities of visiting the addresses to sum to 1,
+// or makes them uniform if they sum to 0. The addresses are summed in order,
+// which keeps the probabilities of seeded agents reproducible.
+func normalize(ps map[graph.Node]float64, addresses []graph.Node) {
+	sum := 0.0
+	for _, ad := range addresses {
+		sum += ps[ad]
+	}
+	for _, ad := range addresses {
+		if _, ok := ps[ad]; !ok {
+			continue
+		}
+		if sum > 0 {
+			ps[ad] /= sum
+		} else {
+			ps[ad] = 1 / float64(len(ps))
+		}
+	}
+}
+
 // WithVisitProb assigns the probability that an agent will traverse
 // to the given address when at the from address.
 func (a *Agent) WithVisitProb(from, to graph.Node, p float64) *Agent {
@@ -115,63 +223,176 @@ func (a *Agent) WithVisitDistribution(d [][]float64) *Agent {
 	return a
 }
 
-// WithState positions the agent in the world.
+// WithSchedule sets time-dependent transition matrices between the agent's
+// addresses. When the agent is at one of its addresses, the matrix of the period
+// containing the agent's current time is used. Outside of the scheduled periods
+// the agent falls back to its visit distribution. It panics unless every
+// matrix is n-by-n for the agent's n addresses.
+func (a *Agent) WithSchedule(s *Schedule) *Agent {
+	checkSchedule(s, len(a.Addresses))
+
+	a.Schedule = s
+	return a
+}
+
+// checkSchedule panics unless every matrix of the schedule is n-by-n.
+func checkSchedule(s *Schedule, n int) {
+	for _, d := range s.transitions {
+		if len(d) != n {
+			panic(fmt.Sprintf("Bad distribution"))
+		}
+		for _, row := range d {
+			if len(row) != n {
+				panic(fmt.Sprintf("Bad distribution"))
+			}
+		}
+	}
+}
+
+// WithState positions the agent in the world. The agent's history
+// records the agent arriving at the node at its current time.
 func (a *Agent) WithState(s graph.Node) *Agent {
 	a.State = s
+	a.History.place(s, a.Time())
 	return a
 }
 
+// Time returns the tick at which the agent is free to take its next walk.
+func (a *Agent) Time() int {
+	if a.clock != nil && a.clock.Tick() > a.time {
+		return a.clock.Tick()
+	}
+	return a.time
+}
+
+// Now returns the wall-clock time of the agent's next walk. Agents without
+// a clock measure their time from the start of a default clock.
+func (a *Agent) Now() time.Time {
+	return a.at(a.Time())
+}
+
+// at returns the wall-clock time of the given tick.
+func (a *Agent) at(tick int) time.Time {
+	if a.clock == nil {
+		return NewClock().Time(tick)
+	}
+	return a.clock.Time(tick)
+}
+
+// Idle returns true if the agent has finished its last walk by the
+// current tick of its clock. Agents without a clock are always idle.
+func (a *Agent) Idle() bool {
+	return a.clock == nil || a.time <= a.clock.Tick()
+}
+
 // Visit moves the agent from the current state
 // to the given node. The agent picks randomly between
-// k shortest paths to that location.
-func (a *Agent) Visit(to graph.Node) {
+// k shortest paths to that location, or by its preferences.
+// It returns false, and the agent stays where it is,
+// if the node is not reachable.
+func (a *Agent) Visit(to graph.Node) bool {
+	return a.visit(to, ReasonVisit)
+}
+
+func (a *Agent) visit(to graph.Node, r Reason) bool {
+	w := a.Route(to)
+	if w == nil {
+		return false
+	}
+	a.follow(w, r)
+	return true
+}
+
+// Route picks the walk that the agent takes from its current state to the
+// given node, between k shortest paths, without moving the agent. It returns
+// nil if the node is not reachable from the agent's state.
+func (a *Agent) Route(to graph.Node) Walk {
 	ps := a.world.KShortestPaths(a.k, a.State, to)
+	if len(ps) == 0 {
+		return nil
+	}
+
 	w := ps[0]
-	if len(ps) > 1 {
-		w = ps[a.rand.Intn(len(ps)-1)]
+	if a.prefers() {
+		w = a.preferredWalk(ps)
+	} else if len(ps) > 1 {
+		w = ps[a.rand.Intn(len(ps))]
 	}
-	a.State = w.End()
-	a.History = append(a.History, w)
+	return w
 }
 
 // Explore picks randomly the length of a random walk
-// and then sets the agent on it.
+// and then sets the agent on it. The walk is biased
+// by the agent's preferences, if any. Agents with
+// a mobility model take the walk of the model instead.
 func (a *Agent) Explore() {
+	a.follow(a.Exploration(), ReasonExplore)
+}
+
+// Exploration picks the walk that the agent takes when it
+// explores, as in Explore, without moving the agent.
+func (a *Agent) Exploration() Walk {
+	if a.mobility != nil {
+		return a.mobility.Walk(a, a.rand)
+	}
+
 	length := a.rand.Intn(a.maxExploreLen) + 1
 
-	w := a.world.RandomWalk(length, a.State)
+	var p path
+	if a.prefers() {
+		p = a.preferredPath(length)
+	} else {
+		p = a.world.randomPath(a.rand, length, a.world.toInt[a.State.String()])
+	}
+	return a.world.toNodes(p)
+}
+
+// follow moves the agent along the walk and records it in the history.
+func (a *Agent) follow(w Walk, r Reason) {
+	a.time = a.History.add(w, a.Time(), r)
 	a.State = w.End()
-	a.History = append(a.History, w)
 }
 
 // VisitOrExplore decides whether to Visit (an address)
 // or Explore the world. If the agent decides to visit
 // an address and the agent is not one of his addresses, then
 // the agent will walk to one of this addresses picked at random.
+//
+// It always follows DefaultPolicy, regardless of the agent's policy; see Step.
 func (a *Agent) VisitAddressOrExplore() {
-	if a.rand.Float64() < a.exploreProb {
-		a.Explore()
-		return
-	}
+	a.follow(DefaultPolicy{}.Next(a, a.world, a.clock))
+}
 
-	// If Agent is not at one of his addresses, then it visits one of them at random.
-	if !graph.Contains(a.Addresses, a.State) {
-		a.Visit(a.Addresses[a.rand.Intn(len(a.Addresses))])
-		return
+// Step moves the agent along the next walk that its policy decides on.
+// It panics if the policy breaks its contract, i.e. if the walk is empty
+// or does not start at the agent's state.
+func (a *Agent) Step() {
+	w, r := a.policy.Next(a, a.world, a.clock)
+	if len(w) == 0 || w[0].String() != a.State.String() {
+		panic(fmt.Sprintf("Bad walk %v from %s", w, a.State))
 	}
-
-	// If Agent is at one of his addresses, then use the transition matrix to visit another address.
-	keys, probs := a.transitionsFrom(a.State)
-	a.Visit(keys[stats.PickFromDiscreteDist(probs)]) // walk there given some short path.
+	a.follow(w, r)
 }
 
 func (a *Agent) transitionsFrom(n graph.Node) ([]graph.Node, []float64) {
+	if a.Schedule != nil {
+		if d, ok := a.Schedule.At(a.Now()); ok {
+			for i, ad := range a.Addresses {
+				if ad.String() == n.String() {
+					return a.Addresses, d[i]
+				}
+			}
+		}
+	}
+
 	probs := make([]float64, 0, 0)
 	keys := make([]graph.Node, 0, 0)
 
-	for key, value := range a.Transitions[a.State] { // pick the distribution from the state
-		probs = append(probs, value)
-		keys = append(keys, key)
+	for _, key := range a.Addresses { // pick the distribution from the state, in a stable order
+		if value, ok := a.Transitions[a.State][key]; ok {
+			probs = append(probs, value)
+			keys = append(keys, key)
+		}
 	}
 
 	return keys, probs
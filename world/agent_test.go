@@ -1,6 +1,7 @@
 package world
 
 import (
+	"fmt"
 	"testing"
 
 	"futurae.com/smallworlds/graph"
@@ -17,11 +18,11 @@ func Test_NewAgent(t *testing.T) {
 	assert.Equal(t, m, a.world)
 	assert.EqualValues(t, []graph.Node{}, a.Addresses)
 	assert.EqualValues(t, map[graph.Node]map[graph.Node]float64{}, a.Transitions)
-	assert.EqualValues(t, []Walk{}, a.History)
+	assert.EqualValues(t, []Walk{}, a.History.Walks())
 }
 
 func Test_AddAddress_Ring(t *testing.T) {
-	g := ring.NewGraph(1, 0).WithSeed(42).WithNodes(2)
+	g := ring.NewGraph(1, 0).WithSeed(42).WithNodes(2).WithShortEdges()
 	m := NewWorld(g)
 	a := NewAgent(m)
 	assert.EqualValues(t, []graph.Node{}, a.Addresses)
@@ -114,7 +115,19 @@ func Test_Visit(t *testing.T) {
 
 	a.Visit(nodes[2])
 
-	assert.EqualValues(t, []Walk{{nodes[0], nodes[3], nodes[2]}}, a.History)
+	assert.EqualValues(t, []Walk{{nodes[0], nodes[3], nodes[2]}}, a.History.Walks())
+}
+
+func Test_Route_AllPaths(t *testing.T) {
+	g := ring.NewGraph(1, 0).WithSeed(42).WithNodes(4).WithShortEdges()
+	nodes := g.Nodes()
+
+	a := NewAgent(NewWorld(g)).WithSeed(42).WithState(nodes[0]).WithK(2)
+	routes := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		routes[fmt.Sprint(a.Route(nodes[2]))] = true
+	}
+	assert.Len(t, routes, 2)
 }
 
 func Test_Explore(t *testing.T) {
@@ -124,8 +137,8 @@ func Test_Explore(t *testing.T) {
 
 	a.Explore()
 
-	assert.Len(t, a.History, 1)
-	assert.True(t, len(a.History[0]) < 3)
+	assert.Len(t, a.History.Walks(), 1)
+	assert.True(t, len(a.History.Walks()[0]) < 3)
 }
 
 func Test_VisitAddressOrExplore_1(t *testing.T) {
@@ -136,8 +149,8 @@ func Test_VisitAddressOrExplore_1(t *testing.T) {
 	a.WithK(2).WithMaxExploreLen(2).WithExploreProb(1.0)
 	a.VisitAddressOrExplore()
 
-	assert.Len(t, a.History, 1)
-	assert.True(t, len(a.History[0]) < 3)
+	assert.Len(t, a.History.Walks(), 1)
+	assert.True(t, len(a.History.Walks()[0]) < 3)
 }
 
 func Test_VisitAddressOrExplore_k4(t *testing.T) {
@@ -152,8 +165,8 @@ func Test_VisitAddressOrExplore_k4(t *testing.T) {
 	a.WithK(4).WithMaxExploreLen(2).WithExploreProb(0.0)
 	a.VisitAddressOrExplore()
 
-	assert.Len(t, a.History, 1)
-	assert.Len(t, a.History[0], 3)
+	assert.Len(t, a.History.Walks(), 1)
+	assert.Len(t, a.History.Walks()[0], 3)
 	assert.Equal(t, g.Nodes()[5], a.State)
 }
 
@@ -169,8 +182,8 @@ func Test_VisitAddressOrExplore_k1(t *testing.T) {
 	a.WithK(1).WithMaxExploreLen(2).WithExploreProb(0.0)
 	a.VisitAddressOrExplore()
 
-	assert.Len(t, a.History, 1)
-	assert.True(t, len(a.History[0]) < 3)
+	assert.Len(t, a.History.Walks(), 1)
+	assert.True(t, len(a.History.Walks()[0]) < 3)
 	assert.Equal(t, g.Nodes()[5], a.State)
 }
 
@@ -186,5 +199,100 @@ func Test_VisitAddressOrExplore_fromRandom(t *testing.T) {
 	a.WithK(1).WithMaxExploreLen(2).WithExploreProb(1.0)
 	a.VisitAddressOrExplore()
 
-	assert.Len(t, a.History, 1)
+	assert.Len(t, a.History.Walks(), 1)
+}
+
+func Test_History(t *testing.T) {
+	g := ring.NewGraph(1, 0).WithSeed(42).WithNodes(6).WithShortEdges()
+	nodes := g.Nodes()
+
+	m := NewWorld(g)
+	a := NewAgent(m).WithSeed(42).WithState(nodes[0]).WithK(1)
+
+	a.Visit(nodes[2])
+	a.Visit(nodes[2])
+	a.WithState(nodes[5])
+	a.Visit(nodes[4])
+
+	assert.Equal(t, 4, a.Time())
+	assert.Equal(t, []Event{
+		{Node: nodes[0], Arrival: 0, Departure: 1, Reason: ReasonStart},
+		{Node: nodes[1], Arrival: 1, Departure: 2, Reason: ReasonVisit},
+		{Node: nodes[2], Arrival: 2, Departure: 3, Reason: ReasonVisit},
+		{Node: nodes[5], Arrival: 3, Departure: 4, Reason: ReasonStart},
+		{Node: nodes[4], Arrival: 4, Departure: -1, Reason: ReasonVisit},
+	}, a.History.Events)
+
+	assert.Equal(t, []Walk{
+		{nodes[0], nodes[1], nodes[2]},
+		{nodes[2]},
+		{nodes[5], nodes[4]},
+	}, a.History.Walks())
+
+	assert.Equal(t, 1, a.History.Events[2].Dwell())
+	assert.Equal(t, -1, a.History.Events[4].Dwell())
+	assert.Equal(t, "visit", a.History.Events[4].Reason.String())
+}
+
+func Test_History_Reasons(t *testing.T) {
+	g := ring.NewGraph(1, 0).WithSeed(42).WithNodes(10).WithShortEdges()
+	nodes := g.Nodes()
+
+	m := NewWorld(g).WithSeed(42)
+	a := NewAgent(m).WithSeed(42).
+		WithState(nodes[1]).
+		WithAddress(nodes[5]).
+		WithExploreProb(0.0)
+
+	a.VisitAddressOrExplore()
+	a.WithExploreProb(1.0).WithMaxExploreLen(3)
+	a.VisitAddressOrExplore()
+
+	for _, e := range a.History.Events[1:5] {
+		assert.Equal(t, ReasonAddress, e.Reason)
+	}
+	for _, e := range a.History.Events[5:] {
+		assert.Equal(t, ReasonExplore, e.Reason)
+	}
+}
+
+func Test_WithClock(t *testing.T) {
+	g := ring.NewGraph(1, 0).WithSeed(42).WithNodes(6).WithShortEdges()
+	nodes := g.Nodes()
+
+	c := NewClock()
+	m := NewWorld(g)
+	a := NewAgent(m).WithState(nodes[0]).WithClock(c)
+
+	c.Advance()
+	c.Advance()
+	assert.True(t, a.Idle())
+
+	a.Visit(nodes[3])
+	assert.False(t, a.Idle())
+	assert.Equal(t, 5, a.Time())
+	assert.Equal(t, 3, a.History.Events[0].Dwell())
+}
+
+func Test_WithSeed_Reproducible(t *testing.T) {
+	trace := func() []Event {
+		g := grid.NewGraph(8, 8).WithSeed(42).WithAllNodes().WithShortEdges(1).WithDistantEdges(1, 2)
+		nodes := g.Nodes()
+
+		a := NewAgent(NewWorld(g)).
+			WithSeed(42).
+			WithState(nodes[0]).
+			WithAddress(nodes[0]).
+			WithAddress(nodes[20]).
+			WithAddress(nodes[40]).
+			WithVisitDistribution([][]float64{{0.1, 0.6, 0.3}, {0.3, 0.1, 0.6}, {0.6, 0.3, 0.1}}).
+			WithExploreProb(0.2)
+
+		for i := 0; i < 50; i++ {
+			a.VisitAddressOrExplore()
+		}
+		return a.History.Events
+	}
+
+	assert.Equal(t, trace(), trace())
 }
@@ -0,0 +1,329 @@
+package world
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"futurae.com/smallworlds/graph"
+)
+
+// Centrality maps nodes onto their importance in the world.
+type Centrality map[graph.Node]float64
+
+// Top returns the k most central nodes in decreasing order of centrality,
+// e.g. to pick popular places as addresses of agents. Ties are ordered by
+// the nodes' strings.
+func (c Centrality) Top(k int) []graph.Node {
+	ns := make([]graph.Node, 0, len(c))
+	for n := range c {
+		ns = append(ns, n)
+	}
+
+	sort.Slice(ns, func(i, j int) bool {
+		if c[ns[i]] == c[ns[j]] {
+			return ns[i].String() < ns[j].String()
+		}
+		return c[ns[i]] > c[ns[j]]
+	})
+
+	if k < len(ns) {
+		ns = ns[:k]
+	}
+	return ns
+}
+
+func (m *World) centrality(values []float64) Centrality {
+	c := make(Centrality, m.n)
+	for i, v := range values {
+		c[m.toNode[i]] = v
+	}
+	return c
+}
+
+// shortestPathDAG holds the shortest paths from a single source, as used
+// by Brandes' algorithm: the nodes in the order in which they were reached,
+// their distances, their numbers of shortest paths, and their predecessors
+// on the shortest paths.
+type shortestPathDAG struct {
+	order []int
+	dist  []float64
+	sigma []float64
+	preds [][]int
+	done  []bool
+}
+
+func newShortestPathDAG(n int) *shortestPathDAG {
+	return &shortestPathDAG{
+		order: make([]int, 0, n),
+		dist:  make([]float64, n, n),
+		sigma: make([]float64, n, n),
+		preds: make([][]int, n, n),
+		done:  make([]bool, n, n),
+	}
+}
+
+func (d *shortestPathDAG) reset() {
+	d.order = d.order[:0]
+	for i := range d.dist {
+		d.dist[i] = -1
+		d.sigma[i] = 0
+		d.preds[i] = d.preds[i][:0]
+		d.done[i] = false
+	}
+}
+
+// reached returns true if v is reachable from the source.
+func (d *shortestPathDAG) reached(v int) bool {
+	return d.dist[v] >= 0
+}
+
+// shortestPathsFrom fills the DAG with the shortest paths from src,
+// which are paths with the fewest hops in unweighted worlds, and
+// the cheapest paths in weighted worlds.
+func (m *World) shortestPathsFrom(d *shortestPathDAG, src int) {
+	d.reset()
+	d.dist[src] = 0
+	d.sigma[src] = 1
+
+	if !m.weighted {
+		d.order = append(d.order, src)
+		for i := 0; i < len(d.order); i++ {
+			u := d.order[i]
+			for _, v := range m.neighbourhood(u) {
+				if !d.reached(v) {
+					d.dist[v] = d.dist[u] + 1
+					d.order = append(d.order, v)
+				}
+				if d.dist[v] == d.dist[u]+1 {
+					d.sigma[v] += d.sigma[u]
+					d.preds[v] = append(d.preds[v], u)
+				}
+			}
+		}
+		return
+	}
+
+	b := distHeap{{node: src, dist: 0}}
+	for len(b) > 0 {
+		u := heap.Pop(&b).(distEntry).node
+		if d.done[u] {
+			continue
+		}
+		d.done[u] = true
+		d.order = append(d.order, u)
+
+		for _, v := range m.neighbourhood(u) {
+			alt := d.dist[u] + m.weight(u, v)
+			switch {
+			case !d.reached(v) || alt < d.dist[v]:
+				d.dist[v] = alt
+				d.sigma[v] = d.sigma[u]
+				d.preds[v] = append(d.preds[v][:0], u)
+				heap.Push(&b, distEntry{node: v, dist: alt})
+			case alt == d.dist[v] && !d.done[v]:
+				d.sigma[v] += d.sigma[u]
+				d.preds[v] = append(d.preds[v], u)
+			}
+		}
+	}
+}
+
+// BetweennessCentrality returns the fraction of shortest paths between
+// pairs of other nodes that pass through each node, computed with Brandes'
+// algorithm in O(nm) time for unweighted worlds. Shortest paths are the
+// cheapest paths in weighted worlds.
+func (m *World) BetweennessCentrality() Centrality {
+	sources := make([]int, m.n, m.n)
+	for i := range sources {
+		sources[i] = i
+	}
+	return m.centrality(m.betweenness(sources))
+}
+
+// ApproxBetweennessCentrality estimates the betweenness centrality from
+// the shortest paths of the given number of sources, which are drawn
+// without replacement with the world's random number generator. It is
+// exact if samples is at least the number of nodes.
+func (m *World) ApproxBetweennessCentrality(samples int) Centrality {
+	if samples >= m.n {
+		return m.BetweennessCentrality()
+	}
+	return m.centrality(m.betweenness(m.rand.Perm(m.n)[:samples]))
+}
+
+func (m *World) betweenness(sources []int) []float64 {
+	bc := make([]float64, m.n, m.n)
+	if m.n <= 2 || len(sources) == 0 {
+		return bc
+	}
+
+	d := newShortestPathDAG(m.n)
+	delta := make([]float64, m.n, m.n)
+	for _, s := range sources {
+		m.shortestPathsFrom(d, s)
+
+		for _, v := range d.order {
+			delta[v] = 0
+		}
+		for i := len(d.order) - 1; i > 0; i-- {
+			w := d.order[i]
+			for _, v := range d.preds[w] {
+				delta[v] += d.sigma[v] / d.sigma[w] * (1 + delta[w])
+			}
+			bc[w] += delta[w]
+		}
+	}
+
+	scale := float64(m.n) / float64(len(sources)) / float64((m.n-1)*(m.n-2))
+	for i := range bc {
+		bc[i] *= scale
+	}
+	return bc
+}
+
+// ClosenessCentrality returns the inverse mean distance from each node to
+// the nodes it reaches, scaled by the fraction of nodes it reaches
+// (Wasserman and Faust), so that nodes of small components are not central.
+func (m *World) ClosenessCentrality() Centrality {
+	cc := make([]float64, m.n, m.n)
+	if m.n <= 1 {
+		return m.centrality(cc)
+	}
+
+	d := newShortestPathDAG(m.n)
+	for u := 0; u < m.n; u++ {
+		m.shortestPathsFrom(d, u)
+
+		sum := 0.0
+		for _, v := range d.order {
+			sum += d.dist[v]
+		}
+		if r := float64(len(d.order) - 1); sum > 0 {
+			cc[u] = r / sum * r / float64(m.n-1)
+		}
+	}
+	return m.centrality(cc)
+}
+
+// HarmonicCentrality returns the mean inverse distance from each node to
+// all other nodes, where unreachable nodes are infinitely far away.
+func (m *World) HarmonicCentrality() Centrality {
+	hc := make([]float64, m.n, m.n)
+	if m.n <= 1 {
+		return m.centrality(hc)
+	}
+
+	d := newShortestPathDAG(m.n)
+	for u := 0; u < m.n; u++ {
+		m.shortestPathsFrom(d, u)
+
+		for _, v := range d.order[1:] {
+			hc[u] += 1 / d.dist[v]
+		}
+		hc[u] /= float64(m.n - 1)
+	}
+	return m.centrality(hc)
+}
+
+const (
+	// centralityTolerance bounds the mean change of the
+	// values of PageRank and eigenvector centrality at convergence.
+	centralityTolerance = 1e-10
+	// maxCentralityIterations bounds the iterations
+	// of PageRank and eigenvector centrality.
+	maxCentralityIterations = 1000
+)
+
+// PageRank returns the stationary distribution of a random surfer who
+// follows an edge with probability damping, and otherwise jumps to a random
+// node. Edges are followed as in random walks, with probabilities inversely
+// proportional to their weights, and nodes without edges jump to a random
+// node. The usual damping is 0.85.
+func (m *World) PageRank(damping float64) Centrality {
+	rank := make([]float64, m.n, m.n)
+	if m.n == 0 {
+		return m.centrality(rank)
+	}
+
+	out := make([]float64, m.n, m.n)
+	for u := 0; u < m.n; u++ {
+		rank[u] = 1 / float64(m.n)
+		for _, v := range m.neighbourhood(u) {
+			out[u] += 1 / m.weight(u, v)
+		}
+	}
+
+	next := make([]float64, m.n, m.n)
+	for i := 0; i < maxCentralityIterations; i++ {
+		dangling := 0.0
+		for u := 0; u < m.n; u++ {
+			if out[u] == 0 {
+				dangling += rank[u]
+			}
+		}
+
+		jump := ((1 - damping) + damping*dangling) / float64(m.n)
+		for v := range next {
+			next[v] = jump
+		}
+		for u := 0; u < m.n; u++ {
+			for _, v := range m.neighbourhood(u) {
+				next[v] += damping * rank[u] / m.weight(u, v) / out[u]
+			}
+		}
+
+		change := 0.0
+		for v := range next {
+			change += math.Abs(next[v] - rank[v])
+		}
+		rank, next = next, rank
+		if change < float64(m.n)*centralityTolerance {
+			break
+		}
+	}
+	return m.centrality(rank)
+}
+
+// EigenvectorCentrality returns the principal eigenvector of the transposed
+// adjacency matrix, in which nodes are central if central nodes link to
+// them. Edges weigh in inversely to their weights. The vector has unit
+// length, and is found by power iteration on the matrix plus the identity,
+// which converges on bipartite worlds too.
+func (m *World) EigenvectorCentrality() Centrality {
+	x := make([]float64, m.n, m.n)
+	if m.n == 0 {
+		return m.centrality(x)
+	}
+
+	for v := range x {
+		x[v] = 1 / float64(m.n)
+	}
+
+	next := make([]float64, m.n, m.n)
+	for i := 0; i < maxCentralityIterations; i++ {
+		copy(next, x)
+		for u := 0; u < m.n; u++ {
+			for _, v := range m.neighbourhood(u) {
+				next[v] += x[u] / m.weight(u, v)
+			}
+		}
+
+		norm := 0.0
+		for _, v := range next {
+			norm += v * v
+		}
+		norm = math.Sqrt(norm)
+
+		change := 0.0
+		for v := range next {
+			next[v] /= norm
+			change += math.Abs(next[v] - x[v])
+		}
+		x, next = next, x
+		if change < float64(m.n)*centralityTolerance {
+			break
+		}
+	}
+	return m.centrality(x)
+}
@@ -0,0 +1,107 @@
+package world
+
+import (
+	"testing"
+
+	"futurae.com/smallworlds/graph"
+	"futurae.com/smallworlds/graph/ring"
+	"github.com/stretchr/testify/assert"
+)
+
+// star links node 0 to the nodes 1 to 4.
+func star() *World {
+	return newMatrixWorld([][]int{
+		{0, 1, 1, 1, 1},
+		{1, 0, 0, 0, 0},
+		{1, 0, 0, 0, 0},
+		{1, 0, 0, 0, 0},
+		{1, 0, 0, 0, 0},
+	})
+}
+
+func Test_BetweennessCentrality(t *testing.T) {
+	c := star().BetweennessCentrality()
+	assert.InDelta(t, 1.0, c[graph.IntNode(0)], 1e-9)
+	assert.InDelta(t, 0.0, c[graph.IntNode(1)], 1e-9)
+
+	// the cheapest path from 0 to 1 goes through 2
+	m := newMatrixWorld([][]int{
+		{0, 3, 1},
+		{3, 0, 1},
+		{1, 1, 0},
+	})
+	m.weighted = true
+	c = m.BetweennessCentrality()
+	assert.InDelta(t, 1.0, c[graph.IntNode(2)], 1e-9)
+	assert.InDelta(t, 0.0, c[graph.IntNode(0)], 1e-9)
+
+	// half the shortest paths between opposite nodes of a square pass a node
+	sq := newMatrixWorld([][]int{
+		{0, 1, 0, 1},
+		{1, 0, 1, 0},
+		{0, 1, 0, 1},
+		{1, 0, 1, 0},
+	})
+	c = sq.BetweennessCentrality()
+	assert.InDelta(t, 1.0/6, c[graph.IntNode(0)], 1e-9)
+}
+
+func Test_ApproxBetweennessCentrality(t *testing.T) {
+	m := NewWorld(ring.NewGraph(2, 0.3).WithSeed(42).WithNodes(60).WithShortEdges().WithDistantEdges()).WithSeed(42)
+	exact := m.BetweennessCentrality()
+
+	assert.Equal(t, exact, m.ApproxBetweennessCentrality(60))
+
+	approx := m.ApproxBetweennessCentrality(30)
+	sum, approxSum := 0.0, 0.0
+	for n, v := range exact {
+		sum += v
+		approxSum += approx[n]
+	}
+	assert.InDelta(t, sum, approxSum, sum*0.2)
+}
+
+func Test_ClosenessCentrality(t *testing.T) {
+	m := newMatrixWorld([][]int{
+		{0, 1, 0, 0},
+		{1, 0, 1, 0},
+		{0, 1, 0, 0},
+		{0, 0, 0, 0},
+	})
+
+	c := m.ClosenessCentrality()
+	assert.InDelta(t, 2.0/2*2.0/3, c[graph.IntNode(1)], 1e-9)
+	assert.InDelta(t, 2.0/3*2.0/3, c[graph.IntNode(0)], 1e-9)
+	assert.Equal(t, 0.0, c[graph.IntNode(3)])
+
+	h := m.HarmonicCentrality()
+	assert.InDelta(t, 2.0/3, h[graph.IntNode(1)], 1e-9)
+	assert.InDelta(t, 1.5/3, h[graph.IntNode(0)], 1e-9)
+	assert.Equal(t, 0.0, h[graph.IntNode(3)])
+}
+
+func Test_PageRank(t *testing.T) {
+	r := NewWorld(ring.NewGraph(1, 0).WithSeed(42).WithNodes(5).WithShortEdges()).PageRank(0.85)
+	for _, v := range r {
+		assert.InDelta(t, 0.2, v, 1e-9)
+	}
+
+	s := star().PageRank(0.85)
+	sum := 0.0
+	for _, v := range s {
+		sum += v
+	}
+	assert.InDelta(t, 1.0, sum, 1e-9)
+	assert.Greater(t, s[graph.IntNode(0)], s[graph.IntNode(1)])
+	assert.Equal(t, graph.IntNode(0), s.Top(1)[0])
+	assert.Equal(t, []graph.Node{graph.IntNode(0), graph.IntNode(1), graph.IntNode(2)}, s.Top(3))
+	assert.Len(t, s.Top(10), 5)
+}
+
+func Test_EigenvectorCentrality(t *testing.T) {
+	c := star().EigenvectorCentrality()
+
+	// the principal eigenvector of a star with 4 leaves is (2, 1, 1, 1, 1) / √8
+	assert.InDelta(t, 2/2.8284271247, c[graph.IntNode(0)], 1e-6)
+	assert.InDelta(t, 1/2.8284271247, c[graph.IntNode(3)], 1e-6)
+}
@@ -0,0 +1,52 @@
+package world
+
+import "time"
+
+// Clock is a discrete simulation clock. Time advances in ticks,
+// and every tick lasts a fixed step of wall-clock time.
+type Clock struct {
+	start time.Time
+	step  time.Duration
+	tick  int
+}
+
+// NewClock creates a clock at tick 0. By default, the clock starts at
+// the Unix epoch and every tick lasts one minute.
+func NewClock() *Clock {
+	return &Clock{
+		start: time.Unix(0, 0).UTC(),
+		step:  time.Minute,
+	}
+}
+
+// WithStart is a builder that sets the time of tick 0.
+func (c *Clock) WithStart(t time.Time) *Clock {
+	c.start = t
+	return c
+}
+
+// WithStep is a builder that sets the duration of a tick.
+func (c *Clock) WithStep(d time.Duration) *Clock {
+	c.step = d
+	return c
+}
+
+// Tick returns the current tick.
+func (c *Clock) Tick() int {
+	return c.tick
+}
+
+// Now returns the time of the current tick.
+func (c *Clock) Now() time.Time {
+	return c.Time(c.tick)
+}
+
+// Time returns the time of the given tick.
+func (c *Clock) Time(tick int) time.Time {
+	return c.start.Add(time.Duration(tick) * c.step)
+}
+
+// Advance moves the clock to the next tick.
+func (c *Clock) Advance() {
+	c.tick++
+}
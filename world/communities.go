@@ -0,0 +1,338 @@
+package world
+
+import (
+	"sort"
+
+	"futurae.com/smallworlds/graph"
+)
+
+// Communities assign nodes, keyed by their strings, to communities
+// numbered from 0. Communities are numbered in the order in which their
+// first nodes appear in the world's nodes.
+type Communities map[string]int
+
+// Len returns the number of communities.
+func (c Communities) Len() int {
+	n := 0
+	for _, k := range c {
+		if k+1 > n {
+			n = k + 1
+		}
+	}
+	return n
+}
+
+// Group returns the community of the node, e.g. to colour
+// the nodes of graph.D3JsonWith.
+func (c Communities) Group(n graph.Node) int {
+	return c[n.String()]
+}
+
+// Partition returns the nodes of every community,
+// in the order of the world's nodes.
+func (m *World) Partition(c Communities) [][]graph.Node {
+	p := make([][]graph.Node, c.Len(), c.Len())
+	for _, n := range m.toNode {
+		k := c[n.String()]
+		p[k] = append(p[k], n)
+	}
+	return p
+}
+
+// strength is an undirected edge of a community graph.
+type strength struct {
+	to     int
+	weight float64
+}
+
+// strengths returns the world as an undirected graph, in which nodes are
+// tied by the mean strength of their edges in both directions. An edge's
+// strength is its inverse weight, as in random walks, so that close nodes
+// are tied strongly.
+func (m *World) strengths() [][]strength {
+	g := make([][]strength, m.n, m.n)
+	for i := 0; i < m.n; i++ {
+		for _, j := range m.neighbourhood(i) {
+			s := 0.5 / m.weight(i, j)
+			g[i] = append(g[i], strength{to: j, weight: s})
+			g[j] = append(g[j], strength{to: i, weight: s})
+		}
+	}
+
+	for i := range g {
+		g[i] = merge(g[i])
+	}
+	return g
+}
+
+// merge sorts the strengths by node and sums the strengths to the same node.
+func merge(ss []strength) []strength {
+	sort.Slice(ss, func(i, j int) bool { return ss[i].to < ss[j].to })
+
+	k := 0
+	for j := 0; j < len(ss); j++ {
+		if k > 0 && ss[j].to == ss[k-1].to {
+			ss[k-1].weight += ss[j].weight
+			continue
+		}
+		ss[k] = ss[j]
+		k++
+	}
+	return ss[:k]
+}
+
+// Modularity returns the modularity of the communities, i.e. the fraction
+// of the edge strength within communities less the fraction expected if
+// edges were placed at random. The world is taken to be undirected, with
+// edge strengths as in Louvain.
+func (m *World) Modularity(c Communities) float64 {
+	labels := make([]int, m.n, m.n)
+	for i, n := range m.toNode {
+		labels[i] = c[n.String()]
+	}
+	return modularity(m.strengths(), labels)
+}
+
+func modularity(g [][]strength, labels []int) float64 {
+	total := 0.0
+	in := make(map[int]float64)
+	tot := make(map[int]float64)
+	for i, ss := range g {
+		for _, s := range ss {
+			total += s.weight
+			tot[labels[i]] += s.weight
+			if labels[i] == labels[s.to] {
+				in[labels[i]] += s.weight
+			}
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+
+	keys := make([]int, 0, len(tot))
+	for k := range tot {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	q := 0.0
+	for _, k := range keys {
+		q += in[k]/total - (tot[k]/total)*(tot[k]/total)
+	}
+	return q
+}
+
+// Louvain detects communities by greedily maximizing modularity with the
+// Louvain method (Blondel et al., 2008). Nodes are moved to the neighbouring
+// community with the largest modularity gain until no move improves the
+// modularity, and the communities are then merged into nodes of a coarser
+// graph on which the moves are repeated.
+//
+// Edges are taken to be undirected, and edge strengths are inverse weights.
+// The method is deterministic. It returns the communities and their modularity.
+func (m *World) Louvain() (Communities, float64) {
+	g := m.strengths()
+
+	// labels maps the world's nodes onto the nodes of the current level.
+	labels := make([]int, m.n, m.n)
+	for i := range labels {
+		labels[i] = i
+	}
+
+	for {
+		moved, level := louvainMoves(g)
+		if !moved {
+			break
+		}
+
+		level = relabel(level)
+		for i := range labels {
+			labels[i] = level[labels[i]]
+		}
+		g = aggregate(g, level)
+	}
+
+	c := m.communities(labels)
+	return c, m.Modularity(c)
+}
+
+// louvainMoves runs the first phase of the Louvain method. It returns
+// the community of every node, and whether any node was moved.
+func louvainMoves(g [][]strength) (bool, []int) {
+	n := len(g)
+	community := make([]int, n, n)
+	degree := make([]float64, n, n)
+	tot := make([]float64, n, n)
+	total := 0.0
+	for i, ss := range g {
+		community[i] = i
+		for _, s := range ss {
+			degree[i] += s.weight
+		}
+		tot[i] = degree[i]
+		total += degree[i]
+	}
+	if total == 0 {
+		return false, community
+	}
+
+	moved := false
+	links := make(map[int]float64)
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < n; i++ {
+			for k := range links {
+				delete(links, k)
+			}
+			for _, s := range g[i] {
+				if s.to != i {
+					links[community[s.to]] += s.weight
+				}
+			}
+
+			current := community[i]
+			tot[current] -= degree[i]
+
+			best, bestGain := current, links[current]-tot[current]*degree[i]/total
+			for k, w := range links {
+				gain := w - tot[k]*degree[i]/total
+				if gain > bestGain || (gain == bestGain && best != current && k < best) {
+					best, bestGain = k, gain
+				}
+			}
+
+			tot[best] += degree[i]
+			community[i] = best
+			if best != current {
+				improved, moved = true, true
+			}
+		}
+	}
+	return moved, community
+}
+
+// aggregate merges the nodes of every community into a single node,
+// whose self-loop holds the strength within the community.
+func aggregate(g [][]strength, community []int) [][]strength {
+	n := 0
+	for _, k := range community {
+		if k+1 > n {
+			n = k + 1
+		}
+	}
+
+	agg := make([][]strength, n, n)
+	for i, ss := range g {
+		for _, s := range ss {
+			agg[community[i]] = append(agg[community[i]], strength{to: community[s.to], weight: s.weight})
+		}
+	}
+
+	for i := range agg {
+		agg[i] = merge(agg[i])
+	}
+	return agg
+}
+
+// relabel numbers the labels from 0 in the order of their first appearance.
+func relabel(labels []int) []int {
+	ids := make(map[int]int)
+	relabeled := make([]int, len(labels), len(labels))
+	for i, l := range labels {
+		id, ok := ids[l]
+		if !ok {
+			id = len(ids)
+			ids[l] = id
+		}
+		relabeled[i] = id
+	}
+	return relabeled
+}
+
+func (m *World) communities(labels []int) Communities {
+	c := make(Communities, m.n)
+	for i, l := range relabel(labels) {
+		c[m.toNode[i].String()] = l
+	}
+	return c
+}
+
+// maxLabelPropagationRounds bounds label propagation,
+// which may otherwise oscillate between ties.
+const maxLabelPropagationRounds = 100
+
+// LabelPropagation detects communities by label propagation (Raghavan et al.,
+// 2007). Every node starts with a label of its own, and then repeatedly adopts
+// the label with the largest strength among its neighbours, until every
+// node holds such a label.
+//
+// Edges are taken to be undirected, and edge strengths are inverse weights.
+// Nodes are visited in random order and ties are broken at random, with the
+// world's random number generator. It returns the communities and their
+// modularity.
+func (m *World) LabelPropagation() (Communities, float64) {
+	g := m.strengths()
+
+	labels := make([]int, m.n, m.n)
+	order := make([]int, m.n, m.n)
+	for i := range labels {
+		labels[i] = i
+		order[i] = i
+	}
+
+	weights := make(map[int]float64)
+	best := make([]int, 0)
+	for round := 0; round < maxLabelPropagationRounds; round++ {
+		m.rand.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+		changed := false
+		for _, i := range order {
+			if len(g[i]) == 0 {
+				continue
+			}
+
+			for k := range weights {
+				delete(weights, k)
+			}
+			for _, s := range g[i] {
+				weights[labels[s.to]] += s.weight
+			}
+
+			max := 0.0
+			best = best[:0]
+			for l, w := range weights {
+				switch {
+				case w > max:
+					max = w
+					best = append(best[:0], l)
+				case w == max:
+					best = append(best, l)
+				}
+			}
+
+			if containsInt(best, labels[i]) {
+				continue
+			}
+			sort.Ints(best)
+			labels[i] = best[m.rand.Intn(len(best))]
+			changed = true
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	c := m.communities(labels)
+	return c, m.Modularity(c)
+}
+
+func containsInt(ns []int, n int) bool {
+	for _, i := range ns {
+		if i == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package world
+
+import (
+	"testing"
+
+	"futurae.com/smallworlds/graph"
+	"futurae.com/smallworlds/graph/ring"
+	"github.com/stretchr/testify/assert"
+)
+
+// twoTriangles joins the triangles 0-1-2 and 3-4-5 by the edge 2-3.
+func twoTriangles() *World {
+	return newMatrixWorld([][]int{
+		{0, 1, 1, 0, 0, 0},
+		{1, 0, 1, 0, 0, 0},
+		{1, 1, 0, 1, 0, 0},
+		{0, 0, 1, 0, 1, 1},
+		{0, 0, 0, 1, 0, 1},
+		{0, 0, 0, 1, 1, 0},
+	})
+}
+
+func Test_Modularity(t *testing.T) {
+	m := twoTriangles()
+
+	split := Communities{"0": 0, "1": 0, "2": 0, "3": 1, "4": 1, "5": 1}
+	assert.InDelta(t, 2*(6.0/14-0.25), m.Modularity(split), 1e-9)
+
+	single := Communities{"0": 0, "1": 0, "2": 0, "3": 0, "4": 0, "5": 0}
+	assert.InDelta(t, 0.0, m.Modularity(single), 1e-9)
+}
+
+func Test_Louvain(t *testing.T) {
+	m := twoTriangles()
+
+	c, q := m.Louvain()
+	assert.Equal(t, Communities{"0": 0, "1": 0, "2": 0, "3": 1, "4": 1, "5": 1}, c)
+	assert.InDelta(t, 2*(6.0/14-0.25), q, 1e-9)
+	assert.Equal(t, 2, c.Len())
+	assert.Equal(t, 1, c.Group(graph.IntNode(4)))
+	assert.Equal(t, [][]graph.Node{
+		{graph.IntNode(0), graph.IntNode(1), graph.IntNode(2)},
+		{graph.IntNode(3), graph.IntNode(4), graph.IntNode(5)},
+	}, m.Partition(c))
+
+	r := NewWorld(ring.NewGraph(2, 0).WithSeed(42).WithNodes(40).WithShortEdges())
+	c, q = r.Louvain()
+	assert.Greater(t, c.Len(), 2)
+	assert.Greater(t, q, 0.5)
+
+	empty := NewWorld(ring.NewGraph(1, 0).WithSeed(42).WithNodes(3))
+	c, q = empty.Louvain()
+	assert.Equal(t, 3, c.Len())
+	assert.Equal(t, 0.0, q)
+}
+
+func Test_LabelPropagation(t *testing.T) {
+	m := twoTriangles().WithSeed(42)
+
+	c, q := m.LabelPropagation()
+	assert.Len(t, c, 6)
+	assert.Equal(t, c["0"], c["1"])
+	assert.Equal(t, c["4"], c["5"])
+	assert.InDelta(t, m.Modularity(c), q, 1e-9)
+
+	r1, q1 := NewWorld(ring.NewGraph(2, 0).WithSeed(42).WithNodes(40).WithShortEdges()).WithSeed(7).LabelPropagation()
+	r2, q2 := NewWorld(ring.NewGraph(2, 0).WithSeed(42).WithNodes(40).WithShortEdges()).WithSeed(7).LabelPropagation()
+	assert.Equal(t, r1, r2)
+	assert.Equal(t, q1, q2)
+}
@@ -0,0 +1,106 @@
+package world
+
+import "futurae.com/smallworlds/graph"
+
+// ConnectedComponents returns the weakly connected components of the
+// world, ordered as in graph.ConnectedComponents.
+func (m *World) ConnectedComponents() [][]graph.Node {
+	return graph.ConnectedComponents(m)
+}
+
+// StronglyConnectedComponents returns the strongly connected components
+// of the world, ordered as in graph.StronglyConnectedComponents. Agents
+// can only travel between addresses in the same strong component.
+func (m *World) StronglyConnectedComponents() [][]graph.Node {
+	return graph.StronglyConnectedComponents(m)
+}
+
+// Connected returns true if every node of the world can reach every other.
+func (m *World) Connected() bool {
+	return len(m.StronglyConnectedComponents()) <= 1
+}
+
+// Reachable returns true if there is a path from one node to the other.
+func (m *World) Reachable(from, to graph.Node) bool {
+	dist := make([]int, m.n, m.n)
+	m.hops(m.toInt[from.String()], dist, make([]int, 0, m.n))
+	return dist[m.toInt[to.String()]] != Unreachable
+}
+
+// sameComponent returns true if the nodes lie in the same strongly
+// connected component, i.e. if they can reach each other. The components
+// are computed once and shared by all agents, until the world changes.
+func (m *World) sameComponent(from, to graph.Node) bool {
+	m.componentsMu.Lock()
+	defer m.componentsMu.Unlock()
+
+	if m.components == nil {
+		m.components = make([]int, m.n, m.n)
+		for c, ns := range m.StronglyConnectedComponents() {
+			for _, n := range ns {
+				m.components[m.toInt[n.String()]] = c
+			}
+		}
+	}
+	return m.components[m.toInt[from.String()]] == m.components[m.toInt[to.String()]]
+}
+
+// LargestComponent creates a world over the largest strongly connected
+// component of the world, with the component's edges and the contexts
+// and features of its nodes, so that agents given addresses in it always
+// reach them. The first of equally large components is taken.
+//
+// The new world keeps the path algorithm, the clock and the representation
+// of the world, and its random number generator is seeded from the world's.
+func (m *World) LargestComponent() *World {
+	var largest []graph.Node
+	for _, c := range m.StronglyConnectedComponents() {
+		if len(c) > len(largest) {
+			largest = c
+		}
+	}
+
+	in := make(map[string]struct{}, len(largest))
+	for _, n := range largest {
+		in[n.String()] = struct{}{}
+	}
+
+	edges := make([]graph.Edge, 0)
+	for _, e := range m.Edges() {
+		_, from := in[e.From().String()]
+		_, to := in[e.To().String()]
+		if from && to {
+			edges = append(edges, e)
+		}
+	}
+
+	sub := NewWorld(subgraph{nodes: largest, edges: edges}).
+		WithSeed(m.rand.Int63()).
+		WithPathAlgorithm(m.pathAlgorithm)
+	if _, ok := m.edges.(adjacencyMatrix); ok {
+		sub.WithAdjacencyMatrix()
+	}
+
+	for _, n := range largest {
+		sub.AddContext(n, NewContext().RightJoin(m.Context(n)))
+		for key, f := range m.features[n.String()] {
+			sub.AddFeature(n, key, f)
+		}
+	}
+	sub.clock = m.clock
+	return sub
+}
+
+// subgraph is a graph given by its nodes and edges.
+type subgraph struct {
+	nodes []graph.Node
+	edges []graph.Edge
+}
+
+func (g subgraph) Nodes() []graph.Node {
+	return g.nodes
+}
+
+func (g subgraph) Edges() []graph.Edge {
+	return g.edges
+}
@@ -0,0 +1,84 @@
+package world
+
+import (
+	"testing"
+
+	"futurae.com/smallworlds/graph"
+	"github.com/stretchr/testify/assert"
+)
+
+// cycleAndTail has the directed cycle 0->1->2->0, the edge 2->3,
+// the undirected edge 3-4 and the isolated node 5.
+func cycleAndTail() *World {
+	return newMatrixWorld([][]int{
+		{0, 1, 0, 0, 0, 0},
+		{0, 0, 1, 0, 0, 0},
+		{1, 0, 0, 1, 0, 0},
+		{0, 0, 0, 0, 1, 0},
+		{0, 0, 0, 1, 0, 0},
+		{0, 0, 0, 0, 0, 0},
+	})
+}
+
+func Test_Components(t *testing.T) {
+	m := cycleAndTail()
+	n := func(i int) graph.Node { return graph.IntNode(i) }
+
+	assert.Equal(t, [][]graph.Node{{n(0), n(1), n(2), n(3), n(4)}, {n(5)}}, m.ConnectedComponents())
+	assert.Equal(t, [][]graph.Node{{n(0), n(1), n(2)}, {n(3), n(4)}, {n(5)}}, m.StronglyConnectedComponents())
+	assert.False(t, m.Connected())
+
+	assert.True(t, m.Reachable(n(0), n(4)))
+	assert.False(t, m.Reachable(n(4), n(0)))
+	assert.False(t, m.Reachable(n(0), n(5)))
+	assert.Empty(t, m.KShortestPaths(2, n(4), n(0)))
+	assert.Empty(t, m.WithPathAlgorithm(Walks).KShortestPaths(2, n(4), n(0)))
+}
+
+func Test_UnreachableAddress(t *testing.T) {
+	m := cycleAndTail()
+	n := func(i int) graph.Node { return graph.IntNode(i) }
+
+	a := NewAgent(m).WithAddresses([]graph.Node{n(0), n(2)})
+	assert.Panics(t, func() { a.WithAddress(n(3)) })
+	assert.Panics(t, func() { a.WithAddress(n(5)) })
+	assert.Panics(t, func() { a.WithAddress(graph.IntNode(9)) })
+	assert.Equal(t, []graph.Node{n(0), n(2)}, a.Addresses)
+
+	// the components follow changes of the world
+	m.AddEdges([]graph.Edge{graph.IntEdge{graph.IntNode(3), graph.IntNode(0)}})
+	a.WithAddress(n(3))
+	assert.Equal(t, []graph.Node{n(0), n(2), n(3)}, a.Addresses)
+	m.RemoveEdges([]graph.Edge{graph.IntEdge{graph.IntNode(3), graph.IntNode(0)}})
+	assert.Panics(t, func() { a.WithAddress(n(4)) })
+
+	m = cycleAndTail()
+	a = NewAgent(m).WithSeed(42).WithExploreProb(0).WithAddresses([]graph.Node{n(0), n(2)}).WithState(n(4))
+	assert.Nil(t, a.Route(n(0)))
+	assert.False(t, a.Visit(n(0)))
+	assert.Equal(t, n(4), a.State)
+
+	_, r := DefaultPolicy{}.Next(a, m, nil)
+	assert.Equal(t, ReasonExplore, r)
+	assert.True(t, a.Visit(n(3)))
+}
+
+func Test_LargestComponent(t *testing.T) {
+	m := cycleAndTail()
+	m.AddContext(graph.IntNode(1), Context{"a": 1})
+
+	l := m.LargestComponent()
+	assert.Equal(t, []graph.Node{graph.IntNode(0), graph.IntNode(1), graph.IntNode(2)}, l.Nodes())
+	assert.Len(t, l.Edges(), 3)
+	assert.True(t, l.Connected())
+	assert.Equal(t, Context{"a": 1}, l.Context(graph.IntNode(1)))
+
+	l.Context(graph.IntNode(1))["a"] = 2
+	assert.Equal(t, 1.0, m.Context(graph.IntNode(1))["a"])
+}
+
+func Test_RandomWalk_DeadEnd(t *testing.T) {
+	m := cycleAndTail().WithSeed(42)
+
+	assert.Equal(t, Walk{graph.IntNode(5)}, m.RandomWalk(5, graph.IntNode(5)))
+}
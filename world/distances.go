@@ -0,0 +1,93 @@
+package world
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"futurae.com/smallworlds/graph"
+)
+
+// Unreachable is the length of paths between nodes
+// that cannot reach each other.
+const Unreachable = -1
+
+// hops fills dist with the number of hops from src to every node,
+// or Unreachable, with a breadth-first search. It returns the
+// reachable nodes in the order of their distance.
+func (m *World) hops(src int, dist []int, queue []int) []int {
+	for i := range dist {
+		dist[i] = Unreachable
+	}
+	dist[src] = 0
+
+	queue = append(queue[:0], src)
+	for i := 0; i < len(queue); i++ {
+		u := queue[i]
+		for _, v := range m.neighbourhood(u) {
+			if dist[v] == Unreachable {
+				dist[v] = dist[u] + 1
+				queue = append(queue, v)
+			}
+		}
+	}
+	return queue
+}
+
+// ShortestPathsLens returns the length of shortest paths between all nodes,
+// or Unreachable. Lengths count the edges on a path regardless of their
+// weights; see ShortestPathsCosts for weighted worlds.
+//
+// It runs a breadth-first search from every node, in O(nm) time, with the
+// sources spread over GOMAXPROCS goroutines. The rows are ordered as the
+// world's nodes, and the matrix takes n² ints; see EachShortestPathsLens
+// for large worlds.
+func (m *World) ShortestPathsLens() [][]int {
+	lens := make([][]int, m.n, m.n)
+
+	var next int64 = -1
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			queue := make([]int, 0, m.n)
+			for {
+				u := int(atomic.AddInt64(&next, 1))
+				if u >= m.n {
+					return
+				}
+
+				lens[u] = make([]int, m.n, m.n)
+				queue = m.hops(u, lens[u], queue)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return lens
+}
+
+// ShortestPathLensFrom returns the length of shortest paths from the given
+// node to all nodes, or Unreachable, ordered as the world's nodes.
+func (m *World) ShortestPathLensFrom(n graph.Node) []int {
+	lens := make([]int, m.n, m.n)
+	m.hops(m.toInt[n.String()], lens, make([]int, 0, m.n))
+	return lens
+}
+
+// EachShortestPathsLens calls f with the lengths of shortest paths from
+// every node, one node at a time, and stops early if f returns false. It
+// takes memory linear in the number of nodes, as the lengths passed to f
+// are reused between calls and must not be retained.
+func (m *World) EachShortestPathsLens(f func(from graph.Node, lens []int) bool) {
+	lens := make([]int, m.n, m.n)
+	queue := make([]int, 0, m.n)
+	for u := 0; u < m.n; u++ {
+		queue = m.hops(u, lens, queue)
+		if !f(m.toNode[u], lens) {
+			return
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package world
+
+import (
+	"testing"
+
+	"futurae.com/smallworlds/graph"
+	"futurae.com/smallworlds/graph/grid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ShortestPathsLens_Unreachable(t *testing.T) {
+	// a directed path 0->1->2 and an isolated node 3
+	m := newMatrixWorld([][]int{
+		{0, 1, 0, 0},
+		{0, 0, 1, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	})
+
+	assert.Equal(t, [][]int{
+		{0, 1, 2, Unreachable},
+		{Unreachable, 0, 1, Unreachable},
+		{Unreachable, Unreachable, 0, Unreachable},
+		{Unreachable, Unreachable, Unreachable, 0},
+	}, m.ShortestPathsLens())
+	assert.Equal(t, []int{Unreachable, 0, 1, Unreachable}, m.ShortestPathLensFrom(graph.IntNode(1)))
+}
+
+func Test_EachShortestPathsLens(t *testing.T) {
+	m := NewWorld(grid.NewGraph(8, 8).WithSeed(42).WithAllNodes().WithShortEdges(1).WithDistantEdges(1, 2))
+	all := m.ShortestPathsLens()
+
+	rows := 0
+	m.EachShortestPathsLens(func(from graph.Node, lens []int) bool {
+		assert.Equal(t, all[rows], lens)
+		assert.Equal(t, m.ShortestPathLensFrom(from), lens)
+		rows++
+		return true
+	})
+	assert.Equal(t, 64, rows)
+
+	rows = 0
+	m.EachShortestPathsLens(func(from graph.Node, lens []int) bool {
+		rows++
+		return rows < 3
+	})
+	assert.Equal(t, 3, rows)
+}
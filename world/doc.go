@@ -1,10 +1,21 @@
 // Package world provides the two key components for building
 // simulations: worlds and agents.
 //
-// A world is a graph with each node assigned a context. An agent lives
+// A world is a graph with each node assigned a context. Contexts may
+// hold features that vary over time, such as the weather or crowds, which
+// are evaluated at the ticks of the world's clock. An agent lives
 // in a world and can explore it by wandering on random walks, or by moving
 // through the world between his points of interest (addresses).
 //
 // When moving between his addresses each agent may take a slightly different route
-// chosen from k shortest routes.
+// chosen from k shortest routes. Agents may prefer or avoid nodes by the features
+// of their contexts, which biases both their routes and their random walks.
+//
+// How agents explore is pluggable: besides random walks, agents may follow
+// mobility models such as Lévy flights, or the exploration and preferential
+// return model, in which agents return to the places they stayed at most.
+//
+// Worlds can be partitioned into communities, e.g. to place the addresses
+// of agents in different neighbourhoods, with Louvain or LabelPropagation,
+// and their nodes ranked by centrality, e.g. to pick popular places.
 package world
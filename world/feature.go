@@ -0,0 +1,186 @@
+package world
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Feature is a context feature whose value varies over time,
+// e.g. the weather at a node or how crowded it is.
+type Feature interface {
+	At(t time.Time) float64
+}
+
+// FeatureFunc adapts a function of time to a feature.
+type FeatureFunc func(t time.Time) float64
+
+// At returns f(t).
+func (f FeatureFunc) At(t time.Time) float64 {
+	return f(t)
+}
+
+// ScheduledFeature takes fixed values during periods of the day,
+// e.g. a venue's opening hours, and a default value otherwise.
+type ScheduledFeature struct {
+	periods   []Period
+	values    []float64
+	otherwise float64
+}
+
+// NewScheduledFeature creates a feature that always takes the given value.
+func NewScheduledFeature(otherwise float64) *ScheduledFeature {
+	return &ScheduledFeature{
+		periods:   make([]Period, 0),
+		values:    make([]float64, 0),
+		otherwise: otherwise,
+	}
+}
+
+// WithValue is a builder that sets the value during the given period.
+// Periods added first take precedence where periods overlap.
+func (f *ScheduledFeature) WithValue(p Period, value float64) *ScheduledFeature {
+	f.periods = append(f.periods, p)
+	f.values = append(f.values, value)
+	return f
+}
+
+// At returns the value of the first period containing t.
+func (f *ScheduledFeature) At(t time.Time) float64 {
+	for i, p := range f.periods {
+		if p.Contains(t) {
+			return f.values[i]
+		}
+	}
+	return f.otherwise
+}
+
+// Sinusoid oscillates around its mean, e.g. the daily rise and fall of
+// crowds. Its phase is measured from midnight in the location of the time
+// at which it is evaluated, thus a daily sinusoid with a peak of 18 hours
+// is greatest at 6 pm. Its period must be positive; see NewSinusoid.
+type Sinusoid struct {
+	Mean      float64
+	Amplitude float64
+	Period    time.Duration
+	Peak      time.Duration
+}
+
+// NewSinusoid creates a sinusoid that is greatest at the given offset
+// into each period. It panics if the period is not positive.
+func NewSinusoid(mean, amplitude float64, period, peak time.Duration) Sinusoid {
+	if period <= 0 {
+		panic(fmt.Sprintf("Bad period %v", period))
+	}
+
+	return Sinusoid{
+		Mean:      mean,
+		Amplitude: amplitude,
+		Period:    period,
+		Peak:      peak,
+	}
+}
+
+// Daily creates a sinusoid with a period of a day
+// that is greatest at the given hour.
+func Daily(mean, amplitude float64, peak int) Sinusoid {
+	return NewSinusoid(mean, amplitude, 24*time.Hour, time.Duration(peak)*time.Hour)
+}
+
+// At returns the value of the sinusoid at t.
+func (s Sinusoid) At(t time.Time) float64 {
+	_, zone := t.Zone()
+	local := time.Duration(t.UnixNano()) + time.Duration(zone)*time.Second
+	phase := float64((local-s.Peak)%s.Period) / float64(s.Period)
+
+	return s.Mean + s.Amplitude*math.Cos(2*math.Pi*phase)
+}
+
+// RandomWalkFeature follows a Gaussian random walk that takes a step
+// at regular intervals from its origin, e.g. the amount of rain. The
+// walk is drawn lazily from its seed, and only its latest step is kept.
+// Earlier times replay the walk from the origin, thus the feature takes
+// the same value whenever it is evaluated at the same time, and is
+// cheapest to evaluate at times that do not go backwards, as a clock's.
+// It may be evaluated concurrently. Times before the origin take the
+// initial value.
+type RandomWalkFeature struct {
+	origin  time.Time
+	step    time.Duration
+	initial float64
+	sigma   float64
+	min     float64
+	max     float64
+
+	mu    sync.Mutex
+	seed  int64
+	rand  *rand.Rand
+	k     int // the latest step drawn
+	value float64
+}
+
+// NewRandomWalkFeature creates a random walk that starts at the initial value
+// at the origin, and moves by normally distributed steps with the given
+// standard deviation. By default the walk is unbounded. It panics if the
+// step is not positive.
+func NewRandomWalkFeature(origin time.Time, step time.Duration, initial, sigma float64) *RandomWalkFeature {
+	if step <= 0 {
+		panic(fmt.Sprintf("Bad step %v", step))
+	}
+
+	f := &RandomWalkFeature{
+		origin:  origin,
+		step:    step,
+		initial: initial,
+		sigma:   sigma,
+		min:     math.Inf(-1),
+		max:     math.Inf(1),
+	}
+	return f.WithSeed(time.Now().UTC().UnixNano())
+}
+
+// WithSeed is a builder that sets the random number generator.
+// It must be called before the feature is evaluated.
+func (f *RandomWalkFeature) WithSeed(seed int64) *RandomWalkFeature {
+	f.seed = seed
+	f.restart()
+	return f
+}
+
+// WithBounds is a builder that keeps the walk within [min, max],
+// e.g. to keep the amount of rain from turning negative.
+func (f *RandomWalkFeature) WithBounds(min, max float64) *RandomWalkFeature {
+	f.min = min
+	f.max = max
+	return f
+}
+
+// At returns the value of the walk after its last step at or before t.
+func (f *RandomWalkFeature) At(t time.Time) float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if t.Before(f.origin) {
+		return f.initial
+	}
+
+	k := int(t.Sub(f.origin) / f.step)
+	if k < f.k {
+		f.restart()
+	}
+	for f.k < k {
+		v := f.value + f.sigma*f.rand.NormFloat64()
+		f.value = math.Max(f.min, math.Min(f.max, v))
+		f.k++
+	}
+	return f.value
+}
+
+// restart rewinds the walk to the origin.
+func (f *RandomWalkFeature) restart() {
+	f.rand = rand.New(rand.NewSource(f.seed))
+	f.k = 0
+	f.value = f.initial
+}
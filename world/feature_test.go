@@ -0,0 +1,87 @@
+package world
+
+import (
+	"testing"
+	"time"
+
+	"futurae.com/smallworlds/graph"
+	"futurae.com/smallworlds/graph/ring"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ScheduledFeature(t *testing.T) {
+	monday := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	open := NewScheduledFeature(0).
+		WithValue(Hours(9, 12, Weekdays...), 1).
+		WithValue(Hours(8, 18), 0.5)
+
+	assert.Equal(t, 1.0, open.At(monday.Add(10*time.Hour)))
+	assert.Equal(t, 0.5, open.At(monday.Add(14*time.Hour)))
+	assert.Equal(t, 0.5, open.At(monday.Add(5*24*time.Hour+10*time.Hour)))
+	assert.Equal(t, 0.0, open.At(monday.Add(20*time.Hour)))
+}
+
+func Test_Sinusoid(t *testing.T) {
+	monday := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	crowd := Daily(0.5, 0.5, 18)
+
+	assert.InDelta(t, 1.0, crowd.At(monday.Add(18*time.Hour)), 1e-9)
+	assert.InDelta(t, 0.0, crowd.At(monday.Add(6*time.Hour)), 1e-9)
+	assert.InDelta(t, 0.5, crowd.At(monday.Add(12*time.Hour)), 1e-9)
+	assert.InDelta(t, 1.0, crowd.At(monday.Add(-6*time.Hour)), 1e-9)
+
+	zurich := time.FixedZone("CET", 3600)
+	assert.InDelta(t, 1.0, crowd.At(time.Date(2021, time.March, 1, 18, 0, 0, 0, zurich)), 1e-9)
+
+	twice := NewSinusoid(0, 1, 12*time.Hour, 0)
+	assert.InDelta(t, -1.0, twice.At(monday.Add(18*time.Hour)), 1e-9)
+	assert.PanicsWithValue(t, "Bad period 0s", func() { NewSinusoid(0, 1, 0, 0) })
+}
+
+func Test_RandomWalkFeature(t *testing.T) {
+	origin := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	rain := func() *RandomWalkFeature {
+		return NewRandomWalkFeature(origin, time.Hour, 0, 1).WithSeed(42).WithBounds(0, 2)
+	}
+
+	r := rain()
+	later := r.At(origin.Add(48 * time.Hour))
+	assert.Equal(t, 0.0, r.At(origin.Add(-time.Hour)))
+	assert.Equal(t, 0.0, r.At(origin.Add(59*time.Minute)))
+	assert.Equal(t, later, r.At(origin.Add(48*time.Hour+30*time.Minute)))
+	assert.Equal(t, later, rain().At(origin.Add(48*time.Hour)))
+
+	values := make([]float64, 48)
+	for h := 0; h < 48; h++ {
+		values[h] = r.At(origin.Add(time.Duration(h) * time.Hour))
+		assert.True(t, values[h] >= 0 && values[h] <= 2)
+	}
+	assert.Equal(t, values[10], r.At(origin.Add(10*time.Hour)))
+	assert.Equal(t, values[47], r.At(origin.Add(47*time.Hour)))
+
+	assert.PanicsWithValue(t, "Bad step 0s", func() { NewRandomWalkFeature(origin, 0, 0, 1) })
+}
+
+func Test_ContextAt(t *testing.T) {
+	monday := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewClock().WithStart(monday.Add(9 * time.Hour)).WithStep(time.Hour)
+	m := NewWorld(ring.NewGraph(1, 0).WithNodes(3).WithShortEdges()).
+		AddContext(graph.IntNode(0), Context{"shop": 1}).
+		AddFeature(graph.IntNode(0), "open", NewScheduledFeature(0).WithValue(Hours(10, 12), 1))
+
+	assert.Equal(t, Context{"shop": 1, "open": 0}, m.ContextAt(graph.IntNode(0), clock.Now()))
+	assert.Equal(t, Context{"shop": 1}, m.Context(graph.IntNode(0)))
+
+	walk := Walk{graph.IntNode(1), graph.IntNode(0)}
+	assert.Equal(t, []Context{{}, {"shop": 1}}, m.Contexts(walk))
+
+	m.WithClock(clock)
+	assert.Equal(t, []Context{{}, {"shop": 1, "open": 1}}, m.Contexts(walk))
+	clock.Advance()
+	clock.Advance()
+	assert.Equal(t, []Context{{}, {"shop": 1, "open": 0}}, m.Contexts(walk))
+	assert.Equal(t, []Context{{}, {"shop": 1, "open": 1}}, m.ContextsAt(walk, clock, 0))
+
+	events := []Event{{Node: graph.IntNode(0), Arrival: 1, Departure: 3}, {Node: graph.IntNode(0), Arrival: 3, Departure: -1}}
+	assert.Equal(t, []Context{{"shop": 1, "open": 1}, {"shop": 1, "open": 0}}, m.EventContexts(events, clock))
+}
@@ -0,0 +1,201 @@
+package world
+
+import "futurae.com/smallworlds/graph"
+
+// Reason tells why an agent moved to a node.
+type Reason int
+
+const (
+	// ReasonStart marks the node at which the agent was placed.
+	ReasonStart Reason = iota
+	// ReasonVisit marks nodes on a walk to a visited node.
+	ReasonVisit
+	// ReasonExplore marks nodes on a random walk.
+	ReasonExplore
+	// ReasonAddress marks nodes on a walk to one of the agent's addresses.
+	ReasonAddress
+)
+
+// String returns the name of the reason.
+func (r Reason) String() string {
+	switch r {
+	case ReasonStart:
+		return "start"
+	case ReasonVisit:
+		return "visit"
+	case ReasonExplore:
+		return "explore"
+	case ReasonAddress:
+		return "address"
+	}
+	return "unknown"
+}
+
+// Event records an agent's stay at a node during the ticks [Arrival, Departure).
+// Departure is -1 while the agent has not left the node.
+type Event struct {
+	Node      graph.Node
+	Arrival   int
+	Departure int
+	Reason    Reason
+}
+
+// Departed returns true if the agent has left the event's node.
+func (e Event) Departed() bool {
+	return e.Departure >= 0
+}
+
+// Dwell returns the number of ticks the agent stayed at the node,
+// or -1 if the agent has not left the node yet.
+func (e Event) Dwell() int {
+	if !e.Departed() {
+		return -1
+	}
+	return e.Departure - e.Arrival
+}
+
+// History is an agent's trace of timestamped events. A walk starting at tick t
+// reaches its i-th node at tick t+i, and a walk without edges keeps the agent
+// at its node for a tick.
+//
+// Consecutive walks share their boundary node, i.e. the event at which one
+// walk ends is the event at which the next walk starts.
+type History struct {
+	Events []Event
+	walks  []span
+	stays  stays
+}
+
+// stays counts the agent's stays at nodes, i.e. the events at which its
+// walks ended and at which it was placed, as they are recorded, so that
+// mobility models need not scan the history.
+type stays struct {
+	nodes  []graph.Node
+	counts []int
+	index  map[string]int
+	last   int // the last event counted
+}
+
+// count counts the i-th event as a stay, unless it is counted already.
+// Events are counted in order, as they only become stays when recorded.
+func (h *History) count(i int) {
+	s := &h.stays
+	if i <= s.last {
+		return
+	}
+	s.last = i
+
+	n := h.Events[i].Node
+	j, ok := s.index[n.String()]
+	if !ok {
+		j = len(s.nodes)
+		s.index[n.String()] = j
+		s.nodes = append(s.nodes, n)
+		s.counts = append(s.counts, 0)
+	}
+	s.counts[j]++
+}
+
+// span holds the indices of the events at which a walk starts and ends.
+type span struct {
+	start int
+	end   int
+}
+
+// NewHistory creates an empty history.
+func NewHistory() History {
+	return History{
+		Events: make([]Event, 0, 0),
+		walks:  make([]span, 0, 0),
+		stays: stays{
+			nodes:  make([]graph.Node, 0),
+			counts: make([]int, 0),
+			index:  make(map[string]int),
+			last:   -1,
+		},
+	}
+}
+
+// Len returns the number of walks in the history.
+func (h History) Len() int {
+	return len(h.walks)
+}
+
+// Walks maps the history back onto the walks that the agent took.
+func (h History) Walks() []Walk {
+	ws := make([]Walk, len(h.walks), len(h.walks))
+
+	for i, sp := range h.walks {
+		ws[i] = make(Walk, 0, sp.end-sp.start+1)
+		for j := sp.start; j <= sp.end; j++ {
+			ws[i] = append(ws[i], h.Events[j].Node)
+		}
+	}
+	return ws
+}
+
+// last returns the event of the node where the agent currently is.
+func (h *History) last() *Event {
+	if len(h.Events) == 0 {
+		return nil
+	}
+	return &h.Events[len(h.Events)-1]
+}
+
+// place records the agent arriving at n at tick t, unless
+// the agent is already at n.
+func (h *History) place(n graph.Node, t int) {
+	if e := h.last(); e != nil {
+		if e.Node.String() == n.String() && !e.Departed() {
+			return
+		}
+		if !e.Departed() {
+			e.Departure = t
+		}
+	}
+	h.Events = append(h.Events, Event{Node: n, Arrival: t, Departure: -1, Reason: ReasonStart})
+	h.count(len(h.Events) - 1)
+}
+
+// add records the walk starting at tick start, which must begin at the
+// agent's current node. It returns the tick at which the agent is free
+// to take its next walk.
+func (h *History) add(w Walk, start int, r Reason) int {
+	h.place(w[0], start)
+	sp := span{start: len(h.Events) - 1}
+
+	for i := 1; i < len(w); i++ {
+		h.last().Departure = start + i
+		h.Events = append(h.Events, Event{Node: w[i], Arrival: start + i, Departure: -1, Reason: r})
+	}
+
+	sp.end = len(h.Events) - 1
+	h.walks = append(h.walks, sp)
+	h.count(sp.end)
+
+	if len(w) == 1 {
+		return start + 1
+	}
+	return start + len(w) - 1
+}
+
+// Visits counts the arrivals of agents at nodes, keyed by the nodes' strings.
+type Visits map[string]int
+
+// CountVisits counts the arrivals at nodes in the histories of the agents,
+// including the nodes at which the agents were placed.
+func CountVisits(agents []*Agent) Visits {
+	v := make(Visits)
+	for _, a := range agents {
+		for _, e := range a.History.Events {
+			v[e.Node.String()]++
+		}
+	}
+	return v
+}
+
+// Size returns the number of visits of the node as a float, e.g. to size
+// the nodes of graph.D3JsonWith.
+func (v Visits) Size(n graph.Node) float64 {
+	return float64(v[n.String()])
+}
@@ -0,0 +1,28 @@
+package world
+
+import (
+	"testing"
+
+	"futurae.com/smallworlds/graph/ring"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CountVisits(t *testing.T) {
+	g := ring.NewGraph(1, 0).WithSeed(42).WithNodes(6).WithShortEdges()
+	nodes := g.Nodes()
+
+	m := NewWorld(g)
+	a := NewAgent(m).WithSeed(42).WithState(nodes[0]).WithK(1)
+	b := NewAgent(m).WithSeed(42).WithState(nodes[1]).WithK(1)
+	a.Visit(nodes[2])
+	b.Visit(nodes[2])
+
+	v := CountVisits([]*Agent{a, b})
+	assert.Equal(t, Visits{"0": 1, "1": 2, "2": 2}, v)
+	assert.Equal(t, 2.0, v.Size(nodes[1]))
+	assert.Equal(t, 0.0, v.Size(nodes[3]))
+
+	m.AddContext(nodes[1], Context{"floor": 2.7})
+	assert.Equal(t, 2, m.GroupBy("floor")(nodes[1]))
+	assert.Equal(t, 0, m.GroupBy("floor")(nodes[2]))
+}
@@ -0,0 +1,195 @@
+package world
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+
+	"futurae.com/smallworlds/graph"
+	"futurae.com/smallworlds/stats"
+)
+
+// Mobility models how agents explore the world, e.g. by Lévy flights.
+// Walk returns the walk that the agent takes from its current state when it
+// explores, drawing from r, which is the agent's random number generator.
+// The walk must start at the agent's state, and must not move the agent.
+type Mobility interface {
+	Walk(a *Agent, r *rand.Rand) Walk
+}
+
+// WithMobility sets the model of the agent's exploration. By default agents
+// explore on random walks of up to their maximum explore length.
+func (a *Agent) WithMobility(m Mobility) *Agent {
+	a.mobility = m
+	return a
+}
+
+// Spatial nodes measure their distance to other nodes of the same type,
+// e.g. grid.Position.
+type Spatial interface {
+	graph.Node
+	Distance(to graph.Node) int
+}
+
+// LevyFlight jumps to nodes at heavy-tailed distances, which are drawn with
+// probability P(d) ∝ d^-(1+Beta) from 1 to the greatest distance of a node
+// that the agent can reach. The distance between spatial nodes is their
+// Distance, and otherwise the number of hops between them. The agent jumps
+// to a node at the distance closest to the drawn one, and travels there on
+// its route.
+//
+// Song et al. found Beta = 0.55 in human mobility.
+type LevyFlight struct {
+	Beta float64
+
+	// MaxJump bounds the distances of jumps, unless it is 0.
+	MaxJump int
+}
+
+// Walk returns the route of the agent's next jump.
+func (f LevyFlight) Walk(a *Agent, r *rand.Rand) Walk {
+	return f.flight(a, r, nil)
+}
+
+// flight returns the route of a jump to a node that is not avoided, i.e.
+// not a key of avoid, or the walk staying at the agent's state if there is
+// none.
+func (f LevyFlight) flight(a *Agent, r *rand.Rand, avoid map[string]int) Walk {
+	m := a.world
+	dist := make([]int, m.n, m.n)
+	reached := m.hops(m.toInt[a.State.String()], dist, make([]int, 0, m.n))
+	spatial, isSpatial := a.State.(Spatial)
+
+	targets := make([]int, 0, len(reached))
+	lens := make([]int, 0, len(reached))
+	longest := 0
+	for _, v := range reached[1:] {
+		if _, ok := avoid[m.toNode[v].String()]; ok {
+			continue
+		}
+
+		d := dist[v]
+		if isSpatial {
+			d = spatial.Distance(m.toNode[v])
+		}
+		targets = append(targets, v)
+		lens = append(lens, d)
+		if d > longest {
+			longest = d
+		}
+	}
+	if len(targets) == 0 {
+		return Walk{a.State}
+	}
+
+	if f.MaxJump > 0 && f.MaxJump < longest {
+		longest = f.MaxJump
+	}
+	jump := f.jump(r, longest)
+
+	closest := make([]int, 0)
+	gap := -1
+	for i, v := range targets {
+		g := lens[i] - jump
+		if g < 0 {
+			g = -g
+		}
+		if gap < 0 || g < gap {
+			gap = g
+			closest = closest[:0]
+		}
+		if g == gap {
+			closest = append(closest, v)
+		}
+	}
+	return a.Route(m.toNode[closest[r.Intn(len(closest))]])
+}
+
+// jumpSamplers caches the samplers of jump distances by their Beta and
+// longest distance, which agents share, possibly concurrently.
+var jumpSamplers sync.Map
+
+type jumpKey struct {
+	beta    float64
+	longest int
+}
+
+// jump draws a distance from 1 to longest.
+func (f LevyFlight) jump(r *rand.Rand, longest int) int {
+	key := jumpKey{beta: f.Beta, longest: longest}
+	s, ok := jumpSamplers.Load(key)
+	if !ok {
+		weights := make([]float64, longest, longest)
+		for d := 1; d <= longest; d++ {
+			weights[d-1] = math.Pow(float64(d), -1-f.Beta)
+		}
+		s, _ = jumpSamplers.LoadOrStore(key, stats.NewAliasSampler(weights))
+	}
+	return s.(*stats.AliasSampler).Sample(r) + 1
+}
+
+// PreferentialReturn is the exploration and preferential return model of
+// Song et al. An agent that has stayed at S distinct nodes explores a new
+// node with probability Rho·S^-Gamma, and otherwise returns to a node where
+// it stayed before, with a probability proportional to the number of its
+// stays there. Stays are the nodes at which the agent's walks ended, and
+// the nodes at which it was placed.
+//
+// Song et al. found Rho = 0.6 and Gamma = 0.21 in human mobility.
+type PreferentialReturn struct {
+	Rho   float64
+	Gamma float64
+
+	// Explore models the exploration of new nodes. Agents jump by a
+	// LevyFlight with Beta = 0.55 to nodes where they have not stayed
+	// if it is nil.
+	Explore Mobility
+}
+
+// NewPreferentialReturn creates the model with the parameters of Song et al.
+func NewPreferentialReturn() PreferentialReturn {
+	return PreferentialReturn{Rho: 0.6, Gamma: 0.21}
+}
+
+// Walk returns the route of the agent's exploration or return. Agents
+// that cannot explore new nodes return, and vice versa.
+func (p PreferentialReturn) Walk(a *Agent, r *rand.Rand) Walk {
+	nodes, stays := staysOf(a.History)
+
+	if r.Float64() < p.Rho*math.Pow(float64(len(nodes)), -p.Gamma) {
+		if w := p.explore(a, r); len(w) > 1 {
+			return w
+		}
+	}
+
+	weights := make([]float64, len(nodes), len(nodes))
+	total := 0.0
+	for i, n := range nodes {
+		if n.String() != a.State.String() {
+			weights[i] = float64(stays[i])
+			total += weights[i]
+		}
+	}
+	if total == 0 {
+		return p.explore(a, r)
+	}
+	if w := a.Route(nodes[stats.NewAliasSampler(weights).Sample(r)]); w != nil {
+		return w
+	}
+	return Walk{a.State} // the node is no longer reachable
+}
+
+// explore returns the route of the agent's exploration of a new node.
+func (p PreferentialReturn) explore(a *Agent, r *rand.Rand) Walk {
+	if p.Explore != nil {
+		return p.Explore.Walk(a, r)
+	}
+	return LevyFlight{Beta: 0.55}.flight(a, r, a.History.stays.index)
+}
+
+// staysOf returns the nodes at which the agent stayed in the order of its
+// first stay there, and the number of its stays at each. The history keeps
+// them up to date, thus they must not be modified.
+func staysOf(h History) ([]graph.Node, []int) {
+	return h.stays.nodes, h.stays.counts
+}
@@ -0,0 +1,107 @@
+package world
+
+import (
+	"testing"
+
+	"futurae.com/smallworlds/graph"
+	"futurae.com/smallworlds/graph/grid"
+	"futurae.com/smallworlds/graph/ring"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LevyFlight(t *testing.T) {
+	m := NewWorld(ring.NewGraph(1, 0).WithNodes(50).WithShortEdges())
+	start := graph.IntNode(0)
+	a := NewAgent(m).WithSeed(42).WithMobility(LevyFlight{Beta: 0.55})
+
+	jumps := make([]int, 26, 26)
+	for i := 0; i < 1000; i++ {
+		a.WithState(start).Explore()
+		w := a.History.Walks()[a.History.Len()-1]
+		assert.Equal(t, start, w[0])
+		jumps[m.ShortestPathLensFrom(start)[m.toInt[a.State.String()]]]++
+	}
+
+	assert.Equal(t, 0, jumps[0])
+	assert.True(t, jumps[1] > jumps[2])
+	assert.True(t, jumps[2] > jumps[10])
+	assert.True(t, jumps[10] > 0)
+}
+
+func Test_LevyFlight_Spatial(t *testing.T) {
+	g := grid.NewGraph(8, 8).WithSeed(42).WithAllNodes().WithShortEdges(2).WithDistantEdges(1, 2)
+	m := NewWorld(g).WithSeed(42)
+	start := g.Nodes()[0].(grid.Position)
+	a := NewAgent(m).WithSeed(42).WithMobility(LevyFlight{Beta: 0.55, MaxJump: 3})
+
+	for i := 0; i < 200; i++ {
+		a.WithState(start).Explore()
+		d := start.Distance(a.State)
+		assert.True(t, d >= 1 && d <= 3)
+	}
+}
+
+func Test_PreferentialReturn(t *testing.T) {
+	m := NewWorld(ring.NewGraph(2, 0).WithNodes(200).WithShortEdges())
+	a := NewAgent(m).WithSeed(41).WithK(1).WithState(graph.IntNode(0)).WithMobility(NewPreferentialReturn())
+
+	for i := 0; i < 500; i++ {
+		a.Explore()
+	}
+
+	nodes, stays := staysOf(a.History)
+	assert.Len(t, stays, len(nodes))
+	assert.True(t, len(nodes) < 200)
+
+	most := 0
+	for _, s := range stays {
+		if s > most {
+			most = s
+		}
+	}
+	// the frequencies of returns are heavy-tailed
+	assert.True(t, most > 10*501/len(nodes))
+}
+
+func Test_StaysOf(t *testing.T) {
+	m := NewWorld(ring.NewGraph(1, 0).WithNodes(6).WithShortEdges())
+	n := func(i int) graph.Node { return graph.IntNode(i) }
+	a := NewAgent(m).WithSeed(42).WithK(1).WithState(n(0))
+
+	a.Visit(n(2))
+	a.Visit(n(0))
+	a.Visit(n(2))
+
+	nodes, stays := staysOf(a.History)
+	assert.Equal(t, []graph.Node{n(0), n(2)}, nodes)
+	assert.Equal(t, []int{2, 2}, stays)
+}
+
+func Test_StaysOf_Incremental(t *testing.T) {
+	m := NewWorld(ring.NewGraph(2, 0).WithNodes(30).WithShortEdges())
+	a := NewAgent(m).WithSeed(42).WithState(graph.IntNode(0)).WithMobility(NewPreferentialReturn())
+	for i := 0; i < 200; i++ {
+		a.Step()
+		if i == 100 {
+			a.WithState(graph.IntNode(7))
+		}
+	}
+
+	// recount the stays from the events
+	ends := make(map[int]bool)
+	for _, sp := range a.History.walks {
+		ends[sp.end] = true
+	}
+	counts := make(map[string]int)
+	for i, e := range a.History.Events {
+		if ends[i] || e.Reason == ReasonStart {
+			counts[e.Node.String()]++
+		}
+	}
+
+	nodes, stays := staysOf(a.History)
+	assert.Len(t, nodes, len(counts))
+	for i, n := range nodes {
+		assert.Equal(t, counts[n.String()], stays[i])
+	}
+}
@@ -0,0 +1,101 @@
+package world
+
+import (
+	"testing"
+
+	"futurae.com/smallworlds/graph"
+	"futurae.com/smallworlds/graph/ring"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AddNode(t *testing.T) {
+	m := NewWorld(ring.NewGraph(1, 0).WithSeed(42).WithNodes(3).WithShortEdges())
+
+	m.AddNode(graph.StringNode("venue")).AddNode(graph.StringNode("venue"))
+	assert.Len(t, m.Nodes(), 4)
+	assert.True(t, m.HasNode(graph.StringNode("venue")))
+	assert.Equal(t, Context{}, m.Context(graph.StringNode("venue")))
+
+	m.AddEdges([]graph.Edge{graph.TupleEdge{graph.IntNode(0), graph.StringNode("venue")}})
+	assert.True(t, m.HasEdge(graph.IntNode(0), graph.StringNode("venue")))
+	assert.Equal(t, []Walk{{graph.IntNode(1), graph.IntNode(0), graph.StringNode("venue")}},
+		m.KShortestPaths(1, graph.IntNode(1), graph.StringNode("venue")))
+}
+
+func Test_RemoveNode(t *testing.T) {
+	for _, m := range []*World{
+		NewWorld(ring.NewGraph(1, 0).WithSeed(42).WithNodes(5).WithShortEdges().WithWeights()),
+		NewWorld(ring.NewGraph(1, 0).WithSeed(42).WithNodes(5).WithShortEdges().WithWeights()).WithAdjacencyMatrix(),
+	} {
+		n := func(i int) graph.Node { return graph.IntNode(i) }
+		m.AddContext(n(4), Context{"a": 1})
+
+		m.RemoveNode(n(1))
+		m.RemoveNode(graph.StringNode("missing"))
+
+		assert.Equal(t, []graph.Node{n(0), n(4), n(2), n(3)}, m.Nodes())
+		assert.False(t, m.HasNode(n(1)))
+		assert.Equal(t, Context{"a": 1}, m.Context(n(4)))
+		assert.Len(t, m.Edges(), 6)
+		assert.False(t, m.HasEdge(n(0), n(2)))
+		assert.True(t, m.HasEdge(n(4), n(0)))
+		assert.True(t, m.HasEdge(n(3), n(4)))
+		assert.Equal(t, 1.0, m.Weight(n(0), n(4)))
+		assert.Equal(t, []Walk{{n(0), n(4), n(3), n(2)}}, m.KShortestPaths(1, n(0), n(2)))
+
+		m.AddNode(n(1))
+		assert.Len(t, m.Nodes(), 5)
+		assert.Len(t, m.Neighbourhood(n(1)), 0)
+	}
+}
+
+func Test_RemoveNode_Star(t *testing.T) {
+	n := func(i int) graph.Node { return graph.IntNode(i) }
+	edges := make([]graph.Edge, 0)
+	for _, e := range [][2]int{{0, 1}, {0, 2}, {0, 3}, {4, 1}} {
+		edges = append(edges, graph.IntEdge{graph.IntNode(e[0]), graph.IntNode(e[1])})
+		edges = append(edges, graph.IntEdge{graph.IntNode(e[1]), graph.IntNode(e[0])})
+	}
+	g := subgraph{nodes: []graph.Node{n(0), n(1), n(2), n(3), n(4)}, edges: edges}
+
+	for _, m := range []*World{NewWorld(g), NewWorld(g).WithAdjacencyMatrix()} {
+		m.RemoveNode(n(0))
+
+		assert.Equal(t, []graph.Node{n(4), n(1), n(2), n(3)}, m.Nodes())
+		assert.ElementsMatch(t, []graph.Edge{
+			graph.TupleEdge{n(4), n(1)},
+			graph.TupleEdge{n(1), n(4)},
+		}, m.Edges())
+	}
+}
+
+func Test_RemoveAddress(t *testing.T) {
+	g := ring.NewGraph(1, 0).WithSeed(42).WithNodes(6).WithShortEdges()
+	nodes := g.Nodes()
+	m := NewWorld(g)
+
+	s := NewSchedule().With(Hours(8, 17), [][]float64{{0, 0.5, 0.5}, {0.5, 0, 0.5}, {0.5, 0.5, 0}})
+	a := NewAgent(m).WithSeed(42).WithState(nodes[0]).
+		WithAddresses(nodes[:3]).
+		WithVisitDistribution([][]float64{{0, 0.5, 0.5}, {0.5, 0, 0.5}, {0.5, 0.5, 0}}).
+		WithSchedule(s)
+	b := NewAgent(m).WithAddresses(nodes[:3]).WithSchedule(s)
+
+	a.RemoveAddress(nodes[1])
+	a.RemoveAddress(nodes[5])
+
+	assert.Equal(t, []graph.Node{nodes[0], nodes[2]}, a.Addresses)
+	assert.Equal(t, map[graph.Node]map[graph.Node]float64{
+		nodes[0]: {nodes[0]: 0, nodes[2]: 1},
+		nodes[2]: {nodes[0]: 1, nodes[2]: 0},
+	}, a.Transitions)
+
+	d, _ := a.Schedule.At(NewClock().Time(9 * 60))
+	assert.Equal(t, [][]float64{{0, 1}, {1, 0}}, d)
+	assert.Same(t, s, b.Schedule)
+
+	a.RemoveAddress(nodes[0])
+	a.RemoveAddress(nodes[2])
+	assert.Len(t, a.Addresses, 0)
+	a.VisitAddressOrExplore()
+}
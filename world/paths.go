@@ -2,6 +2,9 @@ package world
 
 import (
 	"container/heap"
+	"math/rand"
+	"strconv"
+	"strings"
 )
 
 type path []int
@@ -40,6 +43,32 @@ func (p path) equals(q path) bool {
 	return true
 }
 
+// key returns a string that uniquely identifies the path.
+func (p path) key() string {
+	var b strings.Builder
+	for i, v := range p {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Itoa(v))
+	}
+	return b.String()
+}
+
+// hasPrefix returns true if q is a prefix of p.
+func (p path) hasPrefix(q path) bool {
+	if len(q) > len(p) {
+		return false
+	}
+
+	for i := 0; i < len(q); i++ {
+		if p[i] != q[i] {
+			return false
+		}
+	}
+	return true
+}
+
 type paths []path
 
 func (ps paths) contains(p path) bool {
@@ -59,22 +88,51 @@ func (ps paths) add(p path) paths {
 	return append(ps, p)
 }
 
-func (m *World) randomPath(length int, from int) path {
+// randomPath draws a random walk from r. It does not modify the
+// world, thus walks with distinct generators may be drawn concurrently.
+// Walks end early at nodes without neighbours.
+func (m *World) randomPath(r *rand.Rand, length int, from int) path {
 	acc := newPath(from)
 	current := from
 
 	for i := 0; i < length-1; i++ {
-		ns := m.neighbourhood(current)
-		current := ns[m.rand.Intn(len(ns))]
+		if len(m.neighbourhood(current)) == 0 {
+			break // a dead end, e.g. an isolated node
+		}
+		current = m.randomNeighbour(r, current)
 		acc = append(acc, current)
 	}
 	return acc
 }
 
+// randomNeighbour picks a neighbour of n. In a weighted world, the
+// probability of picking a neighbour is inversely proportional to the
+// weight of the edge leading to it.
+func (m *World) randomNeighbour(r *rand.Rand, n int) int {
+	ns := m.neighbourhood(n)
+	if !m.weighted {
+		return ns[r.Intn(len(ns))]
+	}
+
+	total := 0.0
+	for _, v := range ns {
+		total += 1 / m.weight(n, v)
+	}
+
+	u := r.Float64() * total
+	for _, v := range ns {
+		u -= 1 / m.weight(n, v)
+		if u < 0 {
+			return v
+		}
+	}
+	return ns[len(ns)-1]
+}
+
 type indexedPath struct {
 	value path
 	index int
-	cost  int
+	cost  float64
 }
 
 type pathHeap []*indexedPath
@@ -117,22 +175,39 @@ func (b pathHeap) Empty() bool {
 	return b.Len() == 0
 }
 
-func (m *World) kShortestPaths(k, src, target int) paths {
+// pathCost returns the sum of the edge weights along the path.
+func (m *World) pathCost(p path) float64 {
+	c := 0.0
+	for i := 1; i < len(p); i++ {
+		c += m.weight(p[i-1], p[i])
+	}
+	return c
+}
+
+// kPaths computes the k shortest paths with the world's path algorithm.
+func (m *World) kPaths(k, src, target int) paths {
+	if m.pathAlgorithm == Walks {
+		return m.kShortestWalks(k, src, target)
+	}
+	return m.kShortestPaths(k, src, target)
+}
+
+// kShortestWalks computes at most k shortest walks from src to target.
+// Every node is expanded at most k times, thus the returned walks may
+// revisit nodes.
+func (m *World) kShortestWalks(k, src, target int) paths {
 
 	b := make(pathHeap, 0, 0)
 	paths := make(paths, 0, 0)
-	count := make([]int, len(m.array), len(m.array))
+	count := make([]int, m.n, m.n)
 
 	heap.Init(&b)
 
-	for i := 0; i < len(m.array); i++ {
-		count[i] = 0
-	}
-
-	heap.Push(&b, &indexedPath{value: newPath(src), cost: 1})
+	heap.Push(&b, &indexedPath{value: newPath(src), cost: 0})
 
 	for {
-		p := heap.Pop(&b).(*indexedPath).value
+		ip := heap.Pop(&b).(*indexedPath)
+		p := ip.value
 
 		u := p.end()
 		count[u]++
@@ -145,7 +220,7 @@ func (m *World) kShortestPaths(k, src, target int) paths {
 			for _, v := range m.neighbourhood(u) {
 				heap.Push(&b, &indexedPath{
 					value: p.copyAndAdd(v),
-					cost:  p.cost() + 1})
+					cost:  ip.cost + m.weight(u, v)})
 			}
 		}
 
@@ -157,3 +232,160 @@ func (m *World) kShortestPaths(k, src, target int) paths {
 	return paths
 
 }
+
+type edgeKey struct {
+	from int
+	to   int
+}
+
+// kShortestPaths computes at most k loopless paths from src to target
+// ordered by their cost. It implements Yen's algorithm: the (i+1)-th path
+// deviates from the i-th path at some spur node, and the cheapest deviation
+// not yet found is chosen among all spur nodes.
+func (m *World) kShortestPaths(k, src, target int) paths {
+	found := make(paths, 0, k)
+	if k < 1 {
+		return found
+	}
+
+	first, ok := m.shortestPath(src, target, nil, nil)
+	if !ok {
+		return found
+	}
+	found = append(found, first)
+
+	candidates := make(pathHeap, 0, 0)
+	seen := map[string]struct{}{first.key(): {}}
+
+	for len(found) < k {
+		last := found[len(found)-1]
+
+		for i := 0; i < len(last)-1; i++ {
+			root := last[:i+1]
+
+			removedEdges := make(map[edgeKey]struct{})
+			for _, p := range found {
+				if p.hasPrefix(root) && len(p) > i+1 {
+					removedEdges[edgeKey{p[i], p[i+1]}] = struct{}{}
+				}
+			}
+
+			removedNodes := make(map[int]struct{})
+			for _, v := range root[:i] {
+				removedNodes[v] = struct{}{}
+			}
+
+			spur, ok := m.shortestPath(last[i], target, removedNodes, removedEdges)
+			if !ok {
+				continue
+			}
+
+			p := make(path, 0, i+len(spur))
+			p = append(p, root[:i]...)
+			p = append(p, spur...)
+
+			if _, ok := seen[p.key()]; !ok {
+				seen[p.key()] = struct{}{}
+				heap.Push(&candidates, &indexedPath{value: p, cost: m.pathCost(p)})
+			}
+		}
+
+		if candidates.Empty() {
+			break
+		}
+		found = append(found, heap.Pop(&candidates).(*indexedPath).value)
+	}
+
+	return found
+}
+
+// shortestPath computes the cheapest path from src to target with Dijkstra's
+// algorithm, ignoring the removed nodes and edges. It returns false if the
+// target is unreachable. Ties are broken in favour of lower node indices.
+func (m *World) shortestPath(src, target int, removedNodes map[int]struct{}, removedEdges map[edgeKey]struct{}) (path, bool) {
+	s := m.acquireSearch()
+	defer m.releaseSearch(s)
+
+	s.visit(src, 0, src)
+	b := distHeap{{node: src, dist: 0}}
+
+	for len(b) > 0 {
+		u := heap.Pop(&b).(distEntry).node
+		if s.finished(u) {
+			continue
+		}
+		s.finish(u)
+
+		if u == target {
+			p := newPath(target)
+			for v := target; v != src; {
+				v = s.prev[v]
+				p = append(p, v)
+			}
+			return p.reverse(), true
+		}
+
+		for _, v := range m.neighbourhood(u) {
+			if _, ok := removedNodes[v]; ok {
+				continue
+			}
+			if _, ok := removedEdges[edgeKey{u, v}]; ok {
+				continue
+			}
+
+			d := s.dist[u] + 1
+			if m.weighted {
+				d = s.dist[u] + m.weight(u, v)
+			}
+			if !s.visited(v) || d < s.dist[v] {
+				s.visit(v, d, u)
+				heap.Push(&b, distEntry{node: v, dist: d})
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func (p path) reverse() path {
+	for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+		p[i], p[j] = p[j], p[i]
+	}
+	return p
+}
+
+type distEntry struct {
+	node int
+	dist float64
+}
+
+type distHeap []distEntry
+
+func (b distHeap) Len() int {
+	return len(b)
+}
+
+func (b distHeap) Less(i, j int) bool {
+	if b[i].dist == b[j].dist {
+		return b[i].node < b[j].node
+	}
+	return b[i].dist < b[j].dist
+}
+
+func (b distHeap) Swap(i, j int) {
+	b[i], b[j] = b[j], b[i]
+}
+
+func (b *distHeap) Push(x interface{}) {
+	*b = append(*b, x.(distEntry))
+}
+
+func (b *distHeap) Pop() interface{} {
+	old := *b
+	n := len(old)
+
+	e := old[n-1]
+	*b = old[0 : n-1]
+
+	return e
+}
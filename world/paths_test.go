@@ -9,16 +9,55 @@ import (
 )
 
 func Test_kShortestPaths_Ring_4Nodes_Distant(t *testing.T) {
-	m := NewWorld(ring.NewGraph(1, 0).WithSeed(42).WithNodes(0).WithShortEdges())
-	m.array = [][]int{
+	m := newMatrixWorld([][]int{
 		{0, 1, 1, 1},
 		{1, 0, 1, 1},
 		{1, 1, 0, 1},
-		{1, 1, 1, 0}}
+		{1, 1, 1, 0}})
 
 	kExpected := paths{path{0, 2}, path{0, 3, 2}}
 
-	assert.EqualValues(t, m.kShortestPaths(2, 0, 2), kExpected)
+	assert.EqualValues(t, m.kShortestWalks(2, 0, 2), kExpected)
+	assert.EqualValues(t, paths{path{0, 2}, path{0, 1, 2}}, m.kShortestPaths(2, 0, 2))
+}
+
+func Test_kShortestPaths_Loopless(t *testing.T) {
+	m := NewWorld(grid.NewGraph(10, 10).WithAllNodes().WithShortEdges(2))
+	bottom := m.toInt["(0,0)"]
+	top := m.toInt["(9,9)"]
+
+	ps := m.kShortestPaths(20, bottom, top)
+	assert.Len(t, ps, 20)
+
+	seen := make(map[string]struct{})
+	for i, p := range ps {
+		nodes := make(map[int]struct{})
+		for _, v := range p {
+			nodes[v] = struct{}{}
+		}
+		assert.Len(t, nodes, len(p))
+		assert.Equal(t, bottom, p[0])
+		assert.Equal(t, top, p.end())
+
+		seen[p.key()] = struct{}{}
+		if i > 0 {
+			assert.LessOrEqual(t, m.pathCost(ps[i-1]), m.pathCost(p))
+		}
+	}
+	assert.Len(t, seen, 20)
+}
+
+func Test_kShortestPaths_Exhausted(t *testing.T) {
+	m := NewWorld(ring.NewGraph(1, 0).WithSeed(42).WithNodes(4).WithShortEdges())
+
+	assert.EqualValues(t, paths{path{0, 1, 2}, path{0, 3, 2}}, m.kShortestPaths(5, 0, 2))
+}
+
+func Test_kShortestPaths_Unreachable(t *testing.T) {
+	m := NewWorld(ring.NewGraph(1, 0).WithSeed(42).WithNodes(4))
+
+	assert.Empty(t, m.kShortestPaths(2, 0, 2))
+	assert.Empty(t, m.kShortestWalks(2, 0, 2))
 }
 
 func Test_kShortestPaths_10x10Grid(t *testing.T) {
@@ -30,12 +69,11 @@ func Test_kShortestPaths_10x10Grid(t *testing.T) {
 }
 
 func Test_kShortestPaths_ring_0to0(t *testing.T) {
-	m := NewWorld(ring.NewGraph(1, 0).WithSeed(42).WithNodes(0).WithShortEdges())
-	m.array = [][]int{
+	m := newMatrixWorld([][]int{
 		{0, 1, 1, 1},
 		{1, 0, 1, 1},
 		{1, 1, 0, 1},
-		{1, 1, 1, 0}}
+		{1, 1, 1, 0}})
 
 	kExpected := paths{path{0}}
 	assert.EqualValues(t, m.kShortestPaths(1, 0, 0), kExpected)
@@ -54,7 +92,8 @@ func Test_kShortestPaths_k(t *testing.T) {
 	m := NewWorld(ring.NewGraph(1, 0).WithSeed(42).WithNodes(2).WithShortEdges())
 
 	kExpected := paths{path{1, 0}, path{1, 0, 1, 0}}
-	assert.EqualValues(t, kExpected, m.kShortestPaths(2, 1, 0))
+	assert.EqualValues(t, kExpected, m.kShortestWalks(2, 1, 0))
+	assert.EqualValues(t, paths{path{1, 0}}, m.kShortestPaths(2, 1, 0))
 }
 
 func Test_path_copyAndAdd(t *testing.T) {
@@ -86,21 +125,20 @@ func Test_paths_contains(t *testing.T) {
 
 func Test_RandomPath_ring(t *testing.T) {
 
-	m := NewWorld(ring.NewGraph(1, 0).WithSeed(42).WithNodes(0).WithShortEdges())
-	m.array = [][]int{
+	m := newMatrixWorld([][]int{
 		{0, 1, 1, 1},
 		{1, 0, 1, 1},
 		{1, 1, 0, 1},
-		{1, 1, 1, 0}}
+		{1, 1, 1, 0}})
 
-	assert.Equal(t, len(m.randomPath(3, 0)), 3)
-	assert.Equal(t, len(m.randomPath(4, 0)), 4)
+	assert.Equal(t, len(m.randomPath(m.rand, 3, 0)), 3)
+	assert.Equal(t, len(m.randomPath(m.rand, 4, 0)), 4)
 }
 
 func Test_RandomPath_grid(t *testing.T) {
 
 	m := NewWorld(grid.NewGraph(10, 10).WithAllNodes().WithShortEdges(2))
 
-	assert.Equal(t, len(m.randomPath(3, m.toInt["(0,0)"])), 3)
-	assert.Equal(t, len(m.randomPath(4, m.toInt["(0,0)"])), 4)
+	assert.Equal(t, len(m.randomPath(m.rand, 3, m.toInt["(0,0)"])), 3)
+	assert.Equal(t, len(m.randomPath(m.rand, 4, m.toInt["(0,0)"])), 4)
 }
@@ -0,0 +1,72 @@
+package world
+
+import (
+	"fmt"
+
+	"futurae.com/smallworlds/graph"
+	"futurae.com/smallworlds/stats"
+)
+
+// Policy decides the next walk of an agent, e.g. whether it visits one of
+// its addresses or explores the world. Next returns the walk, which must start
+// at the agent's state, and the reason for taking it, without moving the
+// agent; see Agent.Step. The clock is nil for agents without a clock.
+//
+// Policies draw from the agent's Rand, thus seeded agents stay reproducible,
+// and may build on the agent's Route and Exploration.
+type Policy interface {
+	Next(a *Agent, w *World, c *Clock) (Walk, Reason)
+}
+
+// PolicyFunc adapts a function to a policy.
+type PolicyFunc func(a *Agent, w *World, c *Clock) (Walk, Reason)
+
+// Next returns f(a, w, c).
+func (f PolicyFunc) Next(a *Agent, w *World, c *Clock) (Walk, Reason) {
+	return f(a, w, c)
+}
+
+// WithPolicy sets the policy that decides the agent's walks when it steps.
+// By default agents follow DefaultPolicy. It panics if the policy is nil.
+func (a *Agent) WithPolicy(p Policy) *Agent {
+	if p == nil {
+		panic(fmt.Sprintf("Nil policy"))
+	}
+	a.policy = p
+	return a
+}
+
+// DefaultPolicy is the routine of agents between their addresses. The agent
+// explores with its explore probability, or if it has no addresses. Otherwise
+// an agent away from its addresses returns to one of them picked at random,
+// and an agent at one of its addresses visits another one according to its
+// schedule or visit distribution. An agent that cannot reach the address,
+// e.g. after its edges were removed, explores instead.
+type DefaultPolicy struct{}
+
+// Next returns the agent's next walk, to an address or exploring.
+func (DefaultPolicy) Next(a *Agent, w *World, c *Clock) (Walk, Reason) {
+	if a.rand.Float64() < a.exploreProb {
+		return a.Exploration(), ReasonExplore
+	}
+
+	// An agent without addresses can only explore.
+	if len(a.Addresses) == 0 {
+		return a.Exploration(), ReasonExplore
+	}
+
+	var to graph.Node
+	if !graph.Contains(a.Addresses, a.State) {
+		// If Agent is not at one of his addresses, then it visits one of them at random.
+		to = a.Addresses[a.rand.Intn(len(a.Addresses))]
+	} else {
+		// If Agent is at one of his addresses, then use the transition matrix to visit another address.
+		keys, probs := a.transitionsFrom(a.State)
+		to = keys[stats.PickFromDiscreteDistWith(a.rand, probs)]
+	}
+
+	if w := a.Route(to); w != nil {
+		return w, ReasonAddress // walk there given some short path.
+	}
+	return a.Exploration(), ReasonExplore
+}
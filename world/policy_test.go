@@ -0,0 +1,61 @@
+package world
+
+import (
+	"testing"
+
+	"futurae.com/smallworlds/graph"
+	"futurae.com/smallworlds/graph/ring"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Step_DefaultPolicy(t *testing.T) {
+	g := ring.NewGraph(2, 0).WithNodes(10).WithShortEdges()
+	m := NewWorld(g)
+	agent := func() *Agent {
+		return NewAgent(m).WithSeed(42).WithState(g.Nodes()[1]).
+			WithAddresses([]graph.Node{g.Nodes()[0], g.Nodes()[5]}).
+			WithVisitDistribution([][]float64{{0, 1}, {1, 0}})
+	}
+
+	a, b := agent(), agent()
+	for i := 0; i < 20; i++ {
+		a.Step()
+		b.VisitAddressOrExplore()
+	}
+	assert.Equal(t, b.History, a.History)
+}
+
+func Test_Step_Policy(t *testing.T) {
+	g := ring.NewGraph(1, 0).WithNodes(6).WithShortEdges()
+	m := NewWorld(g)
+	home := g.Nodes()[3]
+	a := NewAgent(m).WithSeed(42).WithK(1).WithState(g.Nodes()[0]).
+		WithPolicy(PolicyFunc(func(a *Agent, w *World, c *Clock) (Walk, Reason) {
+			if a.State == home {
+				return a.Exploration(), ReasonExplore
+			}
+			return a.Route(home), ReasonVisit
+		}))
+
+	a.Step()
+	assert.Equal(t, home, a.State)
+	assert.Equal(t, ReasonVisit, a.History.Events[len(a.History.Events)-1].Reason)
+
+	a.Step()
+	assert.Equal(t, ReasonExplore, a.History.Events[len(a.History.Events)-1].Reason)
+}
+
+func Test_Step_BadPolicy(t *testing.T) {
+	g := ring.NewGraph(1, 0).WithNodes(6).WithShortEdges()
+	m := NewWorld(g)
+	policy := func(w Walk) Policy {
+		return PolicyFunc(func(a *Agent, m *World, c *Clock) (Walk, Reason) {
+			return w, ReasonVisit
+		})
+	}
+
+	assert.Panics(t, func() { NewAgent(m).WithPolicy(nil) })
+	assert.Panics(t, func() { NewAgent(m).WithState(g.Nodes()[0]).WithPolicy(policy(Walk{})).Step() })
+	assert.Panics(t, func() { NewAgent(m).WithState(g.Nodes()[0]).WithPolicy(policy(Walk{g.Nodes()[1]})).Step() })
+	assert.NotPanics(t, func() { NewAgent(m).WithState(g.Nodes()[0]).WithPolicy(policy(Walk{g.Nodes()[0]})).Step() })
+}
@@ -0,0 +1,127 @@
+package world
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"futurae.com/smallworlds/stats"
+)
+
+// Preferences weigh the features of contexts, e.g. {"rain": -2, "sunny": 1}
+// for an agent who avoids rain and seeks the sun. Positive weights attract
+// agents to nodes with high values of a feature, and negative weights repel
+// them. Features without a weight are ignored.
+type Preferences map[string]float64
+
+// Score returns the sum of the context's features weighted by the preferences.
+func (p Preferences) Score(c Context) float64 {
+	score := 0.0
+	for _, key := range p.keys() {
+		score += p[key] * c[key]
+	}
+	return score
+}
+
+// keys returns the weighted features in order, which keeps
+// the sums of scores, and thus seeded agents, reproducible.
+func (p Preferences) keys() []string {
+	keys := make([]string, 0, len(p))
+	for key := range p {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// score weighs the features of the node's context at time t, including
+// its time-varying features, without copying the context.
+func (m *World) score(p Preferences, keys []string, n int, t time.Time) float64 {
+	node := m.toNode[n].String()
+	c, fs := m.contexts[node], m.features[node]
+
+	score := 0.0
+	for _, key := range keys {
+		if f, ok := fs[key]; ok {
+			score += p[key] * f.At(t)
+		} else {
+			score += p[key] * c[key]
+		}
+	}
+	return score
+}
+
+// WithPreferences sets the weights of context features that bias the agent's
+// moves. When exploring, the agent picks the next node with a probability
+// proportional to the exponential of its score, times the inverse weight of
+// the edge leading there. When visiting, the agent picks one of the k shortest
+// paths with a probability proportional to the exponential of the mean score
+// of the nodes on the path. Nodes are scored with their contexts at the ticks
+// at which the agent reaches them, thus scaling all weights up makes the
+// agent choose more greedily.
+func (a *Agent) WithPreferences(p Preferences) *Agent {
+	a.preferences = p
+	a.preferenceKeys = p.keys()
+	return a
+}
+
+// preferredPath draws a walk of at most the given length from the agent's
+// state, one neighbour at a time according to the agent's preferences.
+func (a *Agent) preferredPath(length int) path {
+	m := a.world
+	current := m.toInt[a.State.String()]
+	acc := newPath(current)
+
+	for i := 1; i < length; i++ {
+		ns := m.neighbourhood(current)
+		if len(ns) == 0 {
+			break // a dead end, e.g. an isolated node
+		}
+
+		logits := make([]float64, len(ns), len(ns))
+		for j, v := range ns {
+			logits[j] = m.score(a.preferences, a.preferenceKeys, v, a.at(a.Time()+i)) - math.Log(m.weight(current, v))
+		}
+		current = ns[stats.PickFromDiscreteDistWith(a.rand, softmax(logits))]
+		acc = append(acc, current)
+	}
+	return acc
+}
+
+// preferredWalk picks one of the walks by the mean score of its nodes,
+// which the agent reaches one tick after the other.
+func (a *Agent) preferredWalk(ws []Walk) Walk {
+	logits := make([]float64, len(ws), len(ws))
+	for i, w := range ws {
+		if len(w) < 2 {
+			continue
+		}
+		for j, n := range w[1:] {
+			logits[i] += a.world.score(a.preferences, a.preferenceKeys, a.world.toInt[n.String()], a.at(a.Time()+j+1))
+		}
+		logits[i] /= float64(len(w) - 1)
+	}
+	return ws[stats.PickFromDiscreteDistWith(a.rand, softmax(logits))]
+}
+
+// softmax returns stats.Softmax of the logits shifted by their maximum,
+// which keeps the exponentials of large scores finite.
+func softmax(logits []float64) []float64 {
+	max := math.Inf(-1)
+	for _, l := range logits {
+		if l > max {
+			max = l
+		}
+	}
+
+	shifted := make([]float64, len(logits), len(logits))
+	for i, l := range logits {
+		shifted[i] = l - max
+	}
+	return stats.Softmax(shifted)
+}
+
+// prefers returns true if the agent's moves are biased by preferences.
+func (a *Agent) prefers() bool {
+	return len(a.preferences) > 0
+}
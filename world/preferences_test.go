@@ -0,0 +1,64 @@
+package world
+
+import (
+	"testing"
+	"time"
+
+	"futurae.com/smallworlds/graph/ring"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Preferences_Score(t *testing.T) {
+	p := Preferences{"rain": -2, "sunny": 1}
+
+	assert.Equal(t, -1.0, p.Score(Context{"rain": 1, "sunny": 1, "crowd": 5}))
+	assert.Equal(t, 0.0, p.Score(Context{}))
+}
+
+func Test_Explore_Preferences(t *testing.T) {
+	g := ring.NewGraph(1, 0).WithNodes(6).WithShortEdges()
+	nodes := g.Nodes()
+	m := NewWorld(g).AddContext(nodes[1], Context{"rain": 1})
+	a := NewAgent(m).WithSeed(42).WithMaxExploreLen(2).WithPreferences(Preferences{"rain": -5})
+
+	dry := 0
+	for i := 0; i < 100; i++ {
+		a.WithState(nodes[0])
+		for a.State == nodes[0] {
+			a.Explore()
+		}
+		if a.State == nodes[5] {
+			dry++
+		}
+	}
+	assert.True(t, dry > 95)
+}
+
+func Test_Visit_Preferences(t *testing.T) {
+	g := ring.NewGraph(1, 0).WithNodes(4).WithShortEdges()
+	nodes := g.Nodes()
+	m := NewWorld(g)
+	m.AddFeature(nodes[3], "rain", NewScheduledFeature(0).WithValue(Hours(0, 2), 1))
+
+	via := func(c *Clock) map[string]int {
+		counts := map[string]int{}
+		for i := 0; i < 100; i++ {
+			a := NewAgent(m).WithSeed(int64(i)).WithK(2).WithClock(c).
+				WithPreferences(Preferences{"rain": -10}).
+				WithState(nodes[0])
+			a.Visit(nodes[2])
+			counts[a.History.Walks()[0][1].String()]++
+		}
+		return counts
+	}
+
+	clock := NewClock().WithStep(time.Hour)
+	assert.True(t, via(clock)[nodes[1].String()] > 95)
+
+	// it stops raining at node 3 after two hours
+	clock.Advance()
+	clock.Advance()
+	counts := via(clock)
+	assert.True(t, counts[nodes[1].String()] > 20)
+	assert.True(t, counts[nodes[3].String()] > 20)
+}
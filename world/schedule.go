@@ -0,0 +1,122 @@
+package world
+
+import "time"
+
+var (
+	// Weekdays are the days from Monday to Friday.
+	Weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+	// Weekend are Saturday and Sunday.
+	Weekend = []time.Weekday{time.Saturday, time.Sunday}
+)
+
+// Period is a daily recurring window of time, e.g. weekday mornings.
+// The window spans [From, To) measured from midnight, and wraps past
+// midnight if To is before From. A period without days recurs every day.
+type Period struct {
+	Days []time.Weekday
+	From time.Duration
+	To   time.Duration
+}
+
+// Hours creates a period spanning the hours [from, to) on the given days.
+func Hours(from, to int, days ...time.Weekday) Period {
+	return Period{
+		Days: days,
+		From: time.Duration(from) * time.Hour,
+		To:   time.Duration(to) * time.Hour,
+	}
+}
+
+// Contains returns true if t falls within the period.
+func (p Period) Contains(t time.Time) bool {
+	if len(p.Days) > 0 && !containsDay(p.Days, t.Weekday()) {
+		return false
+	}
+
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+
+	if p.From <= p.To {
+		return p.From <= offset && offset < p.To
+	}
+	return p.From <= offset || offset < p.To
+}
+
+func containsDay(days []time.Weekday, d time.Weekday) bool {
+	for _, day := range days {
+		if day == d {
+			return true
+		}
+	}
+	return false
+}
+
+// Schedule assigns transition matrices between an agent's addresses
+// to periods of time. Each matrix is an n-to-n mapping according to
+// the ordering of the agent's addresses.
+type Schedule struct {
+	periods     []Period
+	transitions [][][]float64
+}
+
+// NewSchedule creates an empty schedule.
+func NewSchedule() *Schedule {
+	return &Schedule{
+		periods:     make([]Period, 0),
+		transitions: make([][][]float64, 0),
+	}
+}
+
+// With is a builder that assigns the transition matrix d to the period p.
+// Periods are matched in the order they were added.
+func (s *Schedule) With(p Period, d [][]float64) *Schedule {
+	s.periods = append(s.periods, p)
+	s.transitions = append(s.transitions, d)
+	return s
+}
+
+// At returns the transition matrix of the first period that contains t.
+// It returns false if no period contains t.
+func (s *Schedule) At(t time.Time) ([][]float64, bool) {
+	for i, p := range s.periods {
+		if p.Contains(t) {
+			return s.transitions[i], true
+		}
+	}
+	return nil, false
+}
+
+// without returns a copy of the schedule without the i-th address. The rows
+// of the transition matrices are scaled to sum to 1 again, and are uniform
+// if they sum to 0.
+func (s *Schedule) without(i int) *Schedule {
+	t := NewSchedule()
+	for k, d := range s.transitions {
+		m := make([][]float64, 0, len(d)-1)
+		for r, row := range d {
+			if r == i {
+				continue
+			}
+
+			scaled := make([]float64, 0, len(row)-1)
+			sum := 0.0
+			for c, p := range row {
+				if c != i {
+					scaled = append(scaled, p)
+					sum += p
+				}
+			}
+			for c := range scaled {
+				if sum > 0 {
+					scaled[c] /= sum
+				} else {
+					scaled[c] = 1 / float64(len(scaled))
+				}
+			}
+			m = append(m, scaled)
+		}
+		t.With(s.periods[k], m)
+	}
+	return t
+}
@@ -0,0 +1,98 @@
+package world
+
+import (
+	"testing"
+	"time"
+
+	"futurae.com/smallworlds/graph"
+	"futurae.com/smallworlds/graph/ring"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Period_Contains(t *testing.T) {
+	monday := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	morning := Hours(7, 10, Weekdays...)
+	assert.True(t, morning.Contains(monday.Add(7*time.Hour)))
+	assert.True(t, morning.Contains(monday.Add(9*time.Hour+59*time.Minute)))
+	assert.False(t, morning.Contains(monday.Add(10*time.Hour)))
+	assert.False(t, morning.Contains(monday.Add(5*24*time.Hour+8*time.Hour)))
+
+	night := Hours(22, 6)
+	assert.True(t, night.Contains(monday.Add(23*time.Hour)))
+	assert.True(t, night.Contains(monday.Add(2*time.Hour)))
+	assert.False(t, night.Contains(monday.Add(12*time.Hour)))
+}
+
+func Test_Schedule_At(t *testing.T) {
+	monday := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	s := NewSchedule().
+		With(Hours(7, 10, Weekdays...), [][]float64{{0, 1}, {0, 1}}).
+		With(Hours(0, 24), [][]float64{{1, 0}, {1, 0}})
+
+	d, ok := s.At(monday.Add(8 * time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, [][]float64{{0, 1}, {0, 1}}, d)
+
+	d, ok = s.At(monday.Add(5*24*time.Hour + 8*time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, [][]float64{{1, 0}, {1, 0}}, d)
+
+	_, ok = NewSchedule().At(monday)
+	assert.False(t, ok)
+}
+
+func Test_WithSchedule(t *testing.T) {
+	g := ring.NewGraph(1, 0).WithSeed(42).WithNodes(6).WithShortEdges()
+	nodes := g.Nodes()
+
+	c := NewClock().
+		WithStart(time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)).
+		WithStep(time.Hour)
+
+	a := NewAgent(NewWorld(g)).
+		WithSeed(42).
+		WithClock(c).
+		WithState(nodes[0]).
+		WithAddress(nodes[0]).
+		WithAddress(nodes[3]).
+		WithVisitDistribution([][]float64{{1, 0}, {0, 1}}).
+		WithSchedule(NewSchedule().
+			With(Hours(8, 9), [][]float64{{0, 1}, {0, 1}}).
+			With(Hours(18, 19), [][]float64{{1, 0}, {1, 0}})).
+		WithExploreProb(0.0)
+
+	for c.Tick() < 24 {
+		if a.Idle() {
+			a.VisitAddressOrExplore()
+		}
+		c.Advance()
+	}
+
+	arrivals := make([]int, 0)
+	for _, e := range a.History.Events {
+		if e.Reason == ReasonAddress && (e.Node == nodes[0] || e.Node == nodes[3]) {
+			arrivals = append(arrivals, e.Arrival)
+		}
+	}
+	assert.Equal(t, []int{11, 21}, arrivals)
+}
+
+func Test_WithSchedule_Bad(t *testing.T) {
+	g := ring.NewGraph(1, 0).WithSeed(42).WithNodes(6).WithShortEdges()
+	a := NewAgent(NewWorld(g)).WithAddress(g.Nodes()[0])
+
+	assert.Panics(t, func() {
+		a.WithSchedule(NewSchedule().With(Hours(8, 9), [][]float64{{0, 1}, {0, 1}}))
+	})
+
+	a.WithAddress(g.Nodes()[1])
+	assert.Panics(t, func() {
+		a.WithSchedule(NewSchedule().With(Hours(8, 9), [][]float64{{0, 1}, {0}}))
+	})
+
+	a.WithSchedule(NewSchedule().With(Hours(8, 9), [][]float64{{0, 1}, {1, 0}}))
+	assert.Panics(t, func() { a.WithAddress(g.Nodes()[2]) })
+	assert.Panics(t, func() { a.WithAddresses([]graph.Node{g.Nodes()[2]}) })
+	assert.Len(t, a.Addresses, 2)
+}
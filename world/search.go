@@ -0,0 +1,64 @@
+package world
+
+// search holds the per-node bookkeeping of a shortest path search. An
+// entry is only valid if its stamp equals the generation of the current
+// search, which lets a search be reused without clearing its slices.
+//
+// Searches are pooled by the world, so concurrent agents do not allocate
+// node-sized slices for every path they compute.
+type search struct {
+	dist []float64
+	prev []int
+	seen []uint32
+	done []uint32
+	gen  uint32
+}
+
+func newSearch(n int) *search {
+	return &search{
+		dist: make([]float64, n, n),
+		prev: make([]int, n, n),
+		seen: make([]uint32, n, n),
+		done: make([]uint32, n, n),
+	}
+}
+
+// acquireSearch takes a search that fits the world from the pool.
+func (m *World) acquireSearch() *search {
+	s, ok := m.searches.Get().(*search)
+	if !ok || len(s.dist) < m.n {
+		s = newSearch(m.n)
+	}
+
+	s.gen++
+	if s.gen == 0 {
+		for i := range s.seen {
+			s.seen[i] = 0
+			s.done[i] = 0
+		}
+		s.gen = 1
+	}
+	return s
+}
+
+func (m *World) releaseSearch(s *search) {
+	m.searches.Put(s)
+}
+
+func (s *search) visit(v int, d float64, prev int) {
+	s.seen[v] = s.gen
+	s.dist[v] = d
+	s.prev[v] = prev
+}
+
+func (s *search) visited(v int) bool {
+	return s.seen[v] == s.gen
+}
+
+func (s *search) finish(v int) {
+	s.done[v] = s.gen
+}
+
+func (s *search) finished(v int) bool {
+	return s.done[v] == s.gen
+}
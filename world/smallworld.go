@@ -0,0 +1,184 @@
+package world
+
+import (
+	"math"
+
+	"futurae.com/smallworlds/graph/random"
+	"futurae.com/smallworlds/graph/ring"
+)
+
+// pathStats returns the eccentricity of every node,
+// and the average path length between reachable nodes.
+//
+// The diagnostics built on it measure path lengths in hops, as
+// ShortestPathsLens does, and expect connected worlds. Pairs of nodes that
+// cannot reach each other are ignored, so diagnostics of disconnected worlds
+// only describe paths within their components.
+func (m *World) pathStats() ([]int, float64) {
+	ecc := make([]int, m.n, m.n)
+	dist := make([]int, m.n, m.n)
+	queue := make([]int, 0, m.n)
+
+	sum, pairs := 0, 0
+	for u := 0; u < m.n; u++ {
+		queue = m.hops(u, dist, queue)
+		for _, v := range queue[1:] {
+			sum += dist[v]
+		}
+		pairs += len(queue) - 1
+		ecc[u] = dist[queue[len(queue)-1]]
+	}
+
+	if pairs == 0 {
+		return ecc, 0
+	}
+	return ecc, float64(sum) / float64(pairs)
+}
+
+// AvgShortestPathLen returns the mean number of hops
+// on the shortest paths between all pairs of distinct nodes.
+func (m *World) AvgShortestPathLen() float64 {
+	_, l := m.pathStats()
+	return l
+}
+
+// Eccentricities returns the greatest number of hops from each node to
+// any other node. The eccentricities are ordered as the world's nodes.
+func (m *World) Eccentricities() []int {
+	ecc, _ := m.pathStats()
+	return ecc
+}
+
+// Diameter returns the greatest eccentricity of the world's nodes.
+func (m *World) Diameter() int {
+	d, _ := extremes(m.Eccentricities())
+	return d
+}
+
+// Radius returns the least eccentricity of the world's nodes.
+func (m *World) Radius() int {
+	_, r := extremes(m.Eccentricities())
+	return r
+}
+
+// extremes returns the greatest and the least eccentricity,
+// which are 0 for empty worlds.
+func extremes(ecc []int) (int, int) {
+	if len(ecc) == 0 {
+		return 0, 0
+	}
+
+	max, min := ecc[0], ecc[0]
+	for _, e := range ecc[1:] {
+		if e > max {
+			max = e
+		}
+		if e < min {
+			min = e
+		}
+	}
+	return max, min
+}
+
+// avgClustering returns the mean of the fraction of links among the
+// neighbours of each node, where nodes with fewer than two neighbours have
+// no clustering.
+//
+// AvgClusteringCoeff counts the links of a node along with the links among
+// its neighbours, which lifts the clustering of sparse random graphs to about
+// 2/(k+1) for mean degree k. The small-world coefficients are only meaningful
+// if random graphs have little clustering, so they use the clustering
+// coefficient of Watts and Strogatz, which only counts links among neighbours.
+func (m *World) avgClustering() float64 {
+	if m.n == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for i := 0; i < m.n; i++ {
+		ns := m.neighbourhood(i)
+		if len(ns) < 2 {
+			continue
+		}
+
+		links := 0
+		for _, j := range ns {
+			links += countCommon(m.neighbourhood(j), ns)
+		}
+		sum += float64(links) / float64(len(ns)*(len(ns)-1))
+	}
+	return sum / float64(m.n)
+}
+
+// SmallWorldSigma returns the small-world coefficient σ = (C/Cr) / (L/Lr),
+// where C and L are the average Watts-Strogatz clustering coefficient and
+// path length of the world, and Cr and Lr are those of random graphs with
+// the same numbers of nodes and edges, averaged over the given number of
+// samples, which is at least 1.
+//
+// Small worlds have σ > 1. The references are generated with seeds drawn from
+// the world's random number generator, and undirected edges are expected.
+func (m *World) SmallWorldSigma(samples int) float64 {
+	cr, lr := m.randomReference(samples)
+	return (m.avgClustering() / cr) / (m.AvgShortestPathLen() / lr)
+}
+
+// SmallWorldOmega returns the small-world coefficient ω = Lr/L - C/Cl,
+// where Lr is the path length of random references as for SmallWorldSigma,
+// and Cl is the clustering coefficient of a ring lattice with the world's
+// mean degree.
+//
+// ω is close to 0 for small worlds, to -1 for lattices, and to 1 for random
+// graphs. C/Cl is capped at 1, as no world should be more clustered than its
+// lattice. Thus sparse worlds, whose lattice has no clustering, count as
+// lattices rather than giving NaN.
+func (m *World) SmallWorldOmega(samples int) float64 {
+	_, lr := m.randomReference(samples)
+	c, cl := m.avgClustering(), m.latticeReference().avgClustering()
+
+	ratio := 1.0
+	if c < cl {
+		ratio = c / cl
+	}
+	return lr/m.AvgShortestPathLen() - ratio
+}
+
+// edgeCount returns the number of undirected edges of the world.
+func (m *World) edgeCount() int {
+	e := 0
+	for i := 0; i < m.n; i++ {
+		e += len(m.neighbourhood(i))
+	}
+	return e / 2
+}
+
+// randomReference returns the mean average clustering coefficient and path
+// length of random graphs with as many nodes and edges as the world.
+// Fewer than one sample count as one.
+func (m *World) randomReference(samples int) (float64, float64) {
+	if samples < 1 {
+		samples = 1
+	}
+
+	c, l := 0.0, 0.0
+	for i := 0; i < samples; i++ {
+		r := NewWorld(random.NewGraph().
+			WithSeed(m.rand.Int63()).
+			WithNodes(m.n).
+			WithUndirectedEdges(m.edgeCount()))
+
+		c += r.avgClustering()
+		l += r.AvgShortestPathLen()
+	}
+	return c / float64(samples), l / float64(samples)
+}
+
+// latticeReference returns a ring lattice with as many nodes
+// as the world, and the world's mean degree rounded to an even number.
+func (m *World) latticeReference() *World {
+	kOver2 := int(math.Round(float64(m.edgeCount()) / float64(m.n)))
+	if kOver2 < 1 {
+		kOver2 = 1
+	}
+	return NewWorld(ring.NewGraph(kOver2, 0).WithNodes(m.n).WithShortEdges())
+}
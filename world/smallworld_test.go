@@ -0,0 +1,67 @@
+package world
+
+import (
+	"math"
+	"testing"
+
+	"futurae.com/smallworlds/graph/random"
+	"futurae.com/smallworlds/graph/ring"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PathDiagnostics(t *testing.T) {
+	// a path 0-1-2-3 and an isolated node 4
+	m := newMatrixWorld([][]int{
+		{0, 1, 0, 0, 0},
+		{1, 0, 1, 0, 0},
+		{0, 1, 0, 1, 0},
+		{0, 0, 1, 0, 0},
+		{0, 0, 0, 0, 0},
+	})
+
+	assert.Equal(t, []int{3, 2, 2, 3, 0}, m.Eccentricities())
+	assert.Equal(t, 3, m.Diameter())
+	assert.Equal(t, 0, m.Radius())
+	assert.InDelta(t, 20.0/12, m.AvgShortestPathLen(), 1e-9)
+
+	r := NewWorld(ring.NewGraph(2, 0).WithSeed(42).WithNodes(20).WithShortEdges())
+	assert.Equal(t, 5, r.Diameter())
+	assert.Equal(t, 5, r.Radius())
+	assert.InDelta(t, float64(sumDistances(r.ShortestPathsLens()))/float64(20*19), r.AvgShortestPathLen(), 1e-9)
+}
+
+func Test_SmallWorldCoefficients(t *testing.T) {
+	sw := NewWorld(ring.NewGraph(3, 0.1).WithSeed(42).WithNodes(200).WithShortEdges().WithDistantEdges()).WithSeed(42)
+	assert.Greater(t, sw.SmallWorldSigma(3), 2.0)
+	assert.InDelta(t, 0.0, sw.SmallWorldOmega(3), 0.5)
+
+	lattice := NewWorld(ring.NewGraph(3, 0).WithSeed(42).WithNodes(200).WithShortEdges()).WithSeed(42)
+	assert.Less(t, lattice.SmallWorldOmega(3), -0.5)
+
+	rnd := NewWorld(random.NewGraph().WithSeed(42).WithNodes(200).WithUndirectedEdges(600)).WithSeed(42)
+	assert.Less(t, rnd.SmallWorldSigma(3), 1.5)
+	assert.Greater(t, rnd.SmallWorldOmega(3), 0.5)
+
+	assert.False(t, math.IsNaN(sw.SmallWorldSigma(0)))
+	assert.False(t, math.IsNaN(sw.SmallWorldOmega(-1)))
+
+	// the lattice of a ring has no clustering
+	sparse := NewWorld(ring.NewGraph(1, 0).WithSeed(42).WithNodes(200).WithShortEdges()).WithSeed(42)
+	omega := sparse.SmallWorldOmega(3)
+	assert.False(t, math.IsNaN(omega))
+	assert.Less(t, omega, -0.5)
+}
+
+func Test_avgClustering(t *testing.T) {
+	// a triangle 0-1-2 with a pendant node 3 at 2
+	m := newMatrixWorld([][]int{
+		{0, 1, 1, 0},
+		{1, 0, 1, 0},
+		{1, 1, 0, 1},
+		{0, 0, 1, 0},
+	})
+	assert.InDelta(t, (1+1+1.0/3)/4, m.avgClustering(), 1e-9)
+
+	r := NewWorld(ring.NewGraph(1, 0).WithSeed(42).WithNodes(4).WithShortEdges())
+	assert.Equal(t, 0.0, r.avgClustering())
+}
@@ -0,0 +1,185 @@
+package world
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"text/tabwriter"
+
+	"futurae.com/smallworlds/graph"
+)
+
+// Summary reports the structure of a world, e.g. to compare
+// the parameters of graph generators.
+type Summary struct {
+	Nodes int `json:"nodes"`
+	Edges int `json:"edges"`
+
+	// InDegrees and OutDegrees are histograms, which count
+	// the nodes with every degree from 0 to the maximum.
+	InDegrees  []int   `json:"in_degrees"`
+	OutDegrees []int   `json:"out_degrees"`
+	MeanDegree float64 `json:"mean_degree"`
+
+	// Density is the fraction of all possible directed edges in the world.
+	Density float64 `json:"density"`
+	// Reciprocity is the fraction of edges whose reverse edge is
+	// in the world, which is 1 for undirected graphs.
+	Reciprocity float64 `json:"reciprocity"`
+	// Assortativity is the correlation between the out-degree of the
+	// source and the in-degree of the target of every edge. It is 0 if
+	// it is undefined, i.e. if all degrees are equal.
+	Assortativity float64 `json:"assortativity"`
+	// Clustering is the world's AvgClusteringCoeff.
+	Clustering float64 `json:"clustering"`
+
+	Components       int `json:"components"`
+	StrongComponents int `json:"strong_components"`
+	LargestComponent int `json:"largest_component"`
+
+	// AvgPathLen, Diameter and Radius count hops, and ignore
+	// pairs of nodes that cannot reach each other.
+	AvgPathLen float64 `json:"avg_path_len"`
+	Diameter   int     `json:"diameter"`
+	Radius     int     `json:"radius"`
+}
+
+// Summarize summarizes any graph by the summary of a world over it.
+func Summarize(g graph.Graph) Summary {
+	return NewWorld(g).Summary()
+}
+
+// Summary reports the structure of the world. It computes the shortest
+// paths from every node, and thus takes O(nm) time.
+func (m *World) Summary() Summary {
+	s := Summary{Nodes: m.n}
+
+	in := make([]int, m.n, m.n)
+	out := make([]int, m.n, m.n)
+	reciprocated := 0
+	for u := 0; u < m.n; u++ {
+		for _, v := range m.neighbourhood(u) {
+			out[u]++
+			in[v]++
+			if m.hasEdge(v, u) {
+				reciprocated++
+			}
+		}
+		s.Edges += out[u]
+	}
+
+	s.InDegrees = histogram(in)
+	s.OutDegrees = histogram(out)
+	if m.n > 0 {
+		s.MeanDegree = float64(s.Edges) / float64(m.n)
+		s.Clustering = m.AvgClusteringCoeff()
+	}
+	if m.n > 1 {
+		s.Density = float64(s.Edges) / float64(m.n*(m.n-1))
+	}
+	if s.Edges > 0 {
+		s.Reciprocity = float64(reciprocated) / float64(s.Edges)
+		s.Assortativity = m.assortativity(in, out)
+	}
+
+	strong := m.StronglyConnectedComponents()
+	s.Components = len(m.ConnectedComponents())
+	s.StrongComponents = len(strong)
+	for _, c := range strong {
+		if len(c) > s.LargestComponent {
+			s.LargestComponent = len(c)
+		}
+	}
+
+	ecc, l := m.pathStats()
+	s.AvgPathLen = l
+	s.Diameter, s.Radius = extremes(ecc)
+	return s
+}
+
+// histogram counts the occurrences of every degree.
+func histogram(degrees []int) []int {
+	max := 0
+	for _, d := range degrees {
+		if d > max {
+			max = d
+		}
+	}
+
+	h := make([]int, max+1, max+1)
+	for _, d := range degrees {
+		h[d]++
+	}
+	return h
+}
+
+// assortativity returns the Pearson correlation between the out-degrees of
+// the sources and the in-degrees of the targets of all edges.
+func (m *World) assortativity(in, out []int) float64 {
+	var sx, sy, sxy, sxx, syy, e float64
+	for u := 0; u < m.n; u++ {
+		for _, v := range m.neighbourhood(u) {
+			x, y := float64(out[u]), float64(in[v])
+			sx += x
+			sy += y
+			sxy += x * y
+			sxx += x * x
+			syy += y * y
+			e++
+		}
+	}
+
+	cov := sxy/e - (sx/e)*(sy/e)
+	vx := sxx/e - (sx/e)*(sx/e)
+	vy := syy/e - (sy/e)*(sy/e)
+	if vx <= 0 || vy <= 0 {
+		return 0
+	}
+	return cov / math.Sqrt(vx*vy)
+}
+
+// JSON renders the summary as a JSON object.
+func (s Summary) JSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// String renders the summary as a table.
+func (s Summary) String() string {
+	var b bytes.Buffer
+	t := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(t, "nodes\t%d\n", s.Nodes)
+	fmt.Fprintf(t, "edges\t%d\n", s.Edges)
+	fmt.Fprintf(t, "mean degree\t%.4g\n", s.MeanDegree)
+	fmt.Fprintf(t, "in-degrees\t%s\n", formatHistogram(s.InDegrees))
+	fmt.Fprintf(t, "out-degrees\t%s\n", formatHistogram(s.OutDegrees))
+	fmt.Fprintf(t, "density\t%.4g\n", s.Density)
+	fmt.Fprintf(t, "reciprocity\t%.4g\n", s.Reciprocity)
+	fmt.Fprintf(t, "assortativity\t%.4g\n", s.Assortativity)
+	fmt.Fprintf(t, "clustering\t%.4g\n", s.Clustering)
+	fmt.Fprintf(t, "components\t%d\n", s.Components)
+	fmt.Fprintf(t, "strong components\t%d\n", s.StrongComponents)
+	fmt.Fprintf(t, "largest component\t%d\n", s.LargestComponent)
+	fmt.Fprintf(t, "avg path length\t%.4g\n", s.AvgPathLen)
+	fmt.Fprintf(t, "diameter\t%d\n", s.Diameter)
+	fmt.Fprintf(t, "radius\t%d\n", s.Radius)
+
+	t.Flush()
+	return b.String()
+}
+
+// formatHistogram lists the non-zero counts as degree:count pairs.
+func formatHistogram(h []int) string {
+	var b bytes.Buffer
+	for d, c := range h {
+		if c == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString(" ")
+		}
+		fmt.Fprintf(&b, "%d:%d", d, c)
+	}
+	return b.String()
+}
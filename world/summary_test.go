@@ -0,0 +1,52 @@
+package world
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"futurae.com/smallworlds/graph/ring"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Summary(t *testing.T) {
+	s := cycleAndTail().Summary()
+
+	assert.Equal(t, 6, s.Nodes)
+	assert.Equal(t, 6, s.Edges)
+	assert.Equal(t, []int{1, 4, 1}, s.InDegrees)
+	assert.Equal(t, []int{1, 4, 1}, s.OutDegrees)
+	assert.InDelta(t, 1.0, s.MeanDegree, 1e-9)
+	assert.InDelta(t, 6.0/30, s.Density, 1e-9)
+	assert.InDelta(t, 2.0/6, s.Reciprocity, 1e-9)
+	assert.Equal(t, 2, s.Components)
+	assert.Equal(t, 3, s.StrongComponents)
+	assert.Equal(t, 3, s.LargestComponent)
+	assert.Equal(t, 4, s.Diameter)
+	assert.Equal(t, 0, s.Radius)
+
+	r := Summarize(ring.NewGraph(2, 0).WithSeed(42).WithNodes(20).WithShortEdges())
+	assert.Equal(t, 80, r.Edges)
+	assert.Equal(t, []int{0, 0, 0, 0, 20}, r.OutDegrees)
+	assert.Equal(t, 1.0, r.Reciprocity)
+	assert.Equal(t, 0.0, r.Assortativity)
+	assert.Equal(t, 1, r.StrongComponents)
+	assert.Equal(t, 5, r.Diameter)
+}
+
+func Test_Summary_Renderings(t *testing.T) {
+	s := Summarize(ring.NewGraph(2, 0).WithSeed(42).WithNodes(20).WithShortEdges())
+
+	text := s.String()
+	assert.Contains(t, text, "nodes              20\n")
+	assert.Contains(t, text, "out-degrees        4:20\n")
+	assert.Equal(t, 15, strings.Count(text, "\n"))
+
+	j, err := s.JSON()
+	assert.NoError(t, err)
+
+	var decoded Summary
+	assert.NoError(t, json.Unmarshal(j, &decoded))
+	assert.Equal(t, s, decoded)
+	assert.Contains(t, string(j), `"avg_path_len"`)
+}
@@ -1,8 +1,11 @@
 package world
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
+	"sort"
+	"sync"
 	"time"
 
 	"futurae.com/smallworlds/graph"
@@ -10,46 +13,78 @@ import (
 
 // World type defines a contextual graph (aka a world)
 // for agents to traverse.
+//
+// Agents only read the world, thus many agents may move through a world
+// concurrently as long as the world is not modified meanwhile.
 type World struct {
 	toInt    map[string]int
-	toNode   map[int]graph.Node
-	array    [][]int
+	toNode   []graph.Node
+	edges    adjacency
+	weighted bool
 	n        int
 	rand     *rand.Rand
 	contexts map[string]Context
+	features map[string]map[string]Feature
+	clock    *Clock
+	searches sync.Pool
+
+	components   []int // the strong component of each node; see component
+	componentsMu sync.Mutex
+
+	pathAlgorithm PathAlgorithm
 }
 
+// PathAlgorithm selects how KShortestPaths computes paths between nodes.
+type PathAlgorithm int
+
+const (
+	// SimplePaths computes loopless paths with Yen's algorithm, i.e. no
+	// path visits a node more than once. It is the default algorithm.
+	SimplePaths PathAlgorithm = iota
+
+	// Walks computes walks with a bounded Dijkstra's expansion, where every
+	// node is expanded at most k times. The walks may revisit nodes.
+	Walks
+)
+
 // NewWorld creates a world from a given graph. The created world
 // has an empty context at each node. Internally the graph is represented
-// as an integer matrix to faciliatate k shortest path computations as agents
-// traverse the world.
+// as integer adjacency lists to faciliatate k shortest path computations as agents
+// traverse the world. The memory footprint thus grows with the number of edges,
+// which allows for worlds with millions of nodes.
+//
+// If the graph provides graph.WeightedEdges, their weights are used as
+// the costs of traversing the edges. Other edges cost 1. It panics if
+// a weight is not positive, since shortest paths are computed with
+// Dijkstra's algorithm, and random walks pick edges by inverse weight.
 func NewWorld(w graph.Graph) *World {
 	toInt := make(map[string]int)
-	toNode := make(map[int]graph.Node)
 	contexts := make(map[string]Context)
+	nodes := w.Nodes()
+	toNode := make([]graph.Node, len(nodes), len(nodes))
 
-	for i, node := range w.Nodes() {
+	for i, node := range nodes {
 		toInt[node.String()] = i
 		toNode[i] = node
 		contexts[node.String()] = NewContext()
 	}
 	n := len(toInt)
 
-	array := make([][]int, n, n)
-	for i := 0; i < n; i++ {
-		array[i] = make([]int, n, n)
-	}
-
+	edges := newAdjacencyList(n)
 	for _, edge := range w.Edges() {
-		array[toInt[edge.From().String()]][toInt[edge.To().String()]] = 1
+		checkWeight(edge)
+		edges.appendEdge(toInt[edge.From().String()], toInt[edge.To().String()], graph.Weight(edge))
 	}
+	edges.compact()
 
 	return &World{
 		n:        n,
-		array:    array,
+		edges:    edges,
+		weighted: edges.weights != nil,
 		toInt:    toInt,
 		toNode:   toNode,
 		contexts: contexts,
+		features: make(map[string]map[string]Feature),
 		rand:     rand.New(rand.NewSource(time.Now().UTC().UnixNano())),
 	}
 }
@@ -61,18 +96,145 @@ func (w *World) WithSeed(seed int64) *World {
 	return w
 }
 
-// AddEdges inserts the given edges into the world.
+// WithClock is a builder that sets the clock at which Contexts
+// evaluates the time-varying features of the world.
+func (w *World) WithClock(c *Clock) *World {
+	w.clock = c
+
+	return w
+}
+
+// WithPathAlgorithm is a builder that sets the algorithm used by KShortestPaths.
+func (w *World) WithPathAlgorithm(a PathAlgorithm) *World {
+	w.pathAlgorithm = a
+
+	return w
+}
+
+// WithAdjacencyMatrix is a builder that switches the internal representation
+// to an n×n matrix. Edge lookups then take constant time, at the cost of
+// memory quadratic in the number of nodes, so this is only sensible for
+// tiny dense graphs.
+func (w *World) WithAdjacencyMatrix() *World {
+	array := newAdjacencyMatrix(w.n)
+	for i := 0; i < w.n; i++ {
+		for _, j := range w.neighbourhood(i) {
+			array.addEdge(i, j, w.weight(i, j))
+		}
+	}
+	w.edges = array
+
+	return w
+}
+
+// AddEdges inserts the given edges into the world. Weights of
+// graph.WeightedEdges overwrite the weights of existing edges.
+// It panics if a weight is not positive; see NewWorld.
 func (m *World) AddEdges(es []graph.Edge) {
 	for _, edge := range es {
-		m.array[m.toInt[edge.From().String()]][m.toInt[edge.To().String()]] = 1
+		weight := checkWeight(edge)
+		if weight != 1 {
+			m.weighted = true
+		}
+		m.edges.addEdge(m.toInt[edge.From().String()], m.toInt[edge.To().String()], weight)
 	}
+	m.components = nil
+}
+
+// checkWeight returns the weight of the edge, and panics if it is not
+// positive and finite.
+func checkWeight(e graph.Edge) float64 {
+	w := graph.Weight(e)
+	if !(w > 0) || math.IsInf(w, 1) {
+		panic(fmt.Sprintf("Bad weight %v of edge %s-%s", w, e.From(), e.To()))
+	}
+	return w
 }
 
 // RemoveEdges deletes the given edges from the world.
 func (m *World) RemoveEdges(es []graph.Edge) {
 	for _, edge := range es {
-		m.array[m.toInt[edge.From().String()]][m.toInt[edge.To().String()]] = 0
+		m.edges.removeEdge(m.toInt[edge.From().String()], m.toInt[edge.To().String()])
 	}
+	m.components = nil
+}
+
+// AddNode inserts the node into the world with an empty context,
+// unless the node is already in the world. The node has no edges;
+// see AddEdges.
+//
+// Like the other mutations, AddNode must not run while agents move through
+// the world, e.g. it may run in a simulation's tick functions.
+func (m *World) AddNode(n graph.Node) *World {
+	if m.HasNode(n) {
+		return m
+	}
+
+	m.toInt[n.String()] = m.n
+	m.toNode = append(m.toNode, n)
+	m.contexts[n.String()] = NewContext()
+	m.n++
+	m.edges = m.edges.resize(m.n)
+	m.components = nil
+
+	return m
+}
+
+// HasNode returns true if the node is in the world.
+func (m *World) HasNode(n graph.Node) bool {
+	_, ok := m.toInt[n.String()]
+	return ok
+}
+
+// RemoveNode deletes the node, its edges and its context from the world.
+// The last node of the world takes the index of the removed node, so that
+// the order of the world's nodes changes.
+//
+// Agents are not updated; see Agent.RemoveAddress, and sim.Simulation's
+// RemoveNode, which also moves the agents at the node.
+func (m *World) RemoveNode(n graph.Node) {
+	i, ok := m.toInt[n.String()]
+	if !ok {
+		return
+	}
+	last := m.n - 1
+
+	for u := 0; u < m.n; u++ {
+		m.edges.removeEdge(u, i)
+	}
+	// removeEdge shifts the neighbourhood, thus range over a copy
+	out := append([]int(nil), m.neighbourhood(i)...)
+	for _, v := range out {
+		m.edges.removeEdge(i, v)
+	}
+
+	if i != last {
+		for _, v := range m.neighbourhood(last) {
+			to := v
+			if v == last {
+				to = i
+			}
+			m.edges.addEdge(i, to, m.weight(last, v))
+		}
+		for u := 0; u < last; u++ {
+			if m.hasEdge(u, last) {
+				weight := m.weight(u, last)
+				m.edges.removeEdge(u, last)
+				m.edges.addEdge(u, i, weight)
+			}
+		}
+
+		m.toNode[i] = m.toNode[last]
+		m.toInt[m.toNode[i].String()] = i
+	}
+
+	delete(m.toInt, n.String())
+	delete(m.contexts, n.String())
+	delete(m.features, n.String())
+	m.toNode = m.toNode[:last]
+	m.n = last
+	m.edges = m.edges.resize(m.n)
+	m.components = nil
 }
 
 // HasEdge returns true if there is an edge between the given nodes in the graph.
@@ -81,7 +243,21 @@ func (m *World) HasEdge(from graph.Node, to graph.Node) bool {
 }
 
 func (m *World) hasEdge(from, to int) bool {
-	return m.array[from][to] == 1
+	return m.edges.hasEdge(from, to)
+}
+
+// Weight returns the cost of traversing the edge between the given nodes.
+func (m *World) Weight(from graph.Node, to graph.Node) float64 {
+	return m.weight(m.toInt[from.String()], m.toInt[to.String()])
+}
+
+func (m *World) weight(from, to int) float64 {
+	return m.edges.weight(from, to)
+}
+
+// Weighted returns true if any of the world's edges costs other than 1.
+func (m *World) Weighted() bool {
+	return m.weighted
 }
 
 // AddContext sets the context c for the given node.
@@ -90,10 +266,33 @@ func (w *World) AddContext(n graph.Node, c Context) *World {
 	return w
 }
 
+// Attributed graphs attach numeric attributes to their nodes,
+// e.g. the graphs read from files by package graph/io.
+type Attributed interface {
+	Attributes(n graph.Node) map[string]float64
+}
+
+// WithAttributes is a builder that joins the attributes of the world's
+// nodes in the graph into their contexts. Attributes overwrite existing
+// features of the same name, and nodes of the graph that are not in the
+// world are skipped.
+func (w *World) WithAttributes(g Attributed) *World {
+	for _, n := range w.toNode {
+		w.contexts[n.String()].RightJoin(Context(g.Attributes(n)))
+	}
+	return w
+}
+
+// Attributes returns the node's static context as numeric attributes,
+// e.g. for the writers of package graph/io.
+func (w *World) Attributes(n graph.Node) map[string]float64 {
+	return w.Context(n)
+}
+
 // AddContextWithSpread sets the context c for the given node and its nearest neighbours.
 func (w *World) AddContextWithSpread(origin graph.Node, c Context, spread int) {
 	w.AddContext(origin, c)
-	if spread>0 {
+	if spread > 0 {
 		for _, n := range w.Neighbourhood(origin) {
 			w.AddContextWithSpread(n, c, spread-1)
 		}
@@ -108,13 +307,49 @@ func (w *World) AddIfKeyNotExists(n graph.Node, key string, value float64) *Worl
 	return w
 }
 
-// Context returns the node's current context.
+// AddFeature sets the time-varying feature key for the given node. The
+// feature overwrites any static feature of the same name in ContextAt.
+func (w *World) AddFeature(n graph.Node, key string, f Feature) *World {
+	fs, ok := w.features[n.String()]
+	if !ok {
+		fs = make(map[string]Feature)
+		w.features[n.String()] = fs
+	}
+	fs[key] = f
+
+	return w
+}
+
+// Context returns the node's static context, without
+// its time-varying features; see ContextAt.
 func (w *World) Context(node graph.Node) Context {
 	return w.contexts[node.String()]
 }
 
+// ContextAt returns a copy of the node's context joined
+// with the values of its time-varying features at t.
+func (w *World) ContextAt(node graph.Node, t time.Time) Context {
+	fs := w.features[node.String()]
+	c := make(Context, len(w.contexts[node.String()])+len(fs))
+	c.RightJoin(w.contexts[node.String()])
+	for key, f := range fs {
+		c[key] = f.At(t)
+	}
+
+	return c
+}
+
 // Contexts maps the given walk onto a list of respective contexts.
+//
+// If the world has a clock, the walk is taken to start at the clock's current
+// tick and to reach its i-th node i ticks later, as in an agent's history,
+// and the contexts of nodes with time-varying features hold their values at
+// these ticks. Otherwise the contexts are the static contexts of the nodes.
 func (w *World) Contexts(walk Walk) []Context {
+	if w.clock != nil {
+		return w.ContextsAt(walk, w.clock, w.clock.Tick())
+	}
+
 	ctxs := make([]Context, len(walk), len(walk))
 	for i := 0; i < len(walk); i++ {
 		ctxs[i] = w.contexts[walk[i].String()]
@@ -123,26 +358,87 @@ func (w *World) Contexts(walk Walk) []Context {
 	return ctxs
 }
 
-// Nodes returns all nodes in the graph.
+// ContextsAt maps the given walk onto a list of respective contexts, taking
+// the walk to start at the given tick of the clock, e.g. a simulation's clock,
+// and to reach its i-th node i ticks later.
+func (w *World) ContextsAt(walk Walk, c *Clock, tick int) []Context {
+	ctxs := make([]Context, len(walk), len(walk))
+	for i := 0; i < len(walk); i++ {
+		ctxs[i] = w.contextAtTick(walk[i], c, tick+i)
+	}
+
+	return ctxs
+}
+
+// EventContexts maps the given events, e.g. of an agent's history, onto a
+// list of respective contexts at the ticks of the clock at which the agent
+// arrived at their nodes.
+func (w *World) EventContexts(events []Event, c *Clock) []Context {
+	ctxs := make([]Context, len(events), len(events))
+	for i, e := range events {
+		ctxs[i] = w.contextAtTick(e.Node, c, e.Arrival)
+	}
+
+	return ctxs
+}
+
+// contextAtTick returns the node's static context if it has
+// no time-varying features, and its context at the tick otherwise.
+func (w *World) contextAtTick(n graph.Node, c *Clock, tick int) Context {
+	if _, ok := w.features[n.String()]; ok {
+		return w.ContextAt(n, c.Time(tick))
+	}
+	return w.contexts[n.String()]
+}
+
+// GroupBy groups nodes by the value of the feature key in their
+// contexts, e.g. to colour the nodes of graph.D3JsonWith. The values
+// are truncated to integers, and nodes without the feature are in group 0.
+func (w *World) GroupBy(key string) func(graph.Node) int {
+	return func(n graph.Node) int {
+		return int(w.contexts[n.String()][key])
+	}
+}
+
+// Nodes returns all nodes in the graph. The nodes are ordered
+// as the rows of ShortestPathsLens.
 func (w *World) Nodes() []graph.Node {
-	ns := make([]graph.Node, 0, 0)
+	ns := make([]graph.Node, len(w.toNode), len(w.toNode))
+	copy(ns, w.toNode)
+	return ns
+}
+
+// EachNode calls f with every node of the world, in the order of Nodes,
+// without collecting them, e.g. to stream large worlds to files.
+func (w *World) EachNode(f func(n graph.Node)) {
 	for _, n := range w.toNode {
-		ns = append(ns, n)
+		f(n)
 	}
-	return ns
 }
 
-// Edges returns all the edges in the graph.
+// Edges returns all the edges in the graph. The edges of a
+// weighted world are graph.WeightedEdges.
 func (w *World) Edges() []graph.Edge {
-	es := make([]graph.Edge, 0, len(w.array))
-	for i := 0; i < len(w.array); i++ {
-		for j := 0; j < len(w.array); j++ {
-			if w.hasEdge(i, j) {
-				es = append(es, graph.TupleEdge{w.toNode[i], w.toNode[j]})
+	es := make([]graph.Edge, 0, w.n)
+	w.EachEdge(func(e graph.Edge) {
+		es = append(es, e)
+	})
+	return es
+}
+
+// EachEdge calls f with every edge of the world, in the order of Edges,
+// without collecting them, e.g. to stream large worlds to files.
+func (w *World) EachEdge(f func(e graph.Edge)) {
+	for i := 0; i < w.n; i++ {
+		for _, j := range w.neighbourhood(i) {
+			e := graph.TupleEdge{w.toNode[i], w.toNode[j]}
+			if w.weighted {
+				f(graph.WeightedTupleEdge{TupleEdge: e, Cost: w.weight(i, j)})
+			} else {
+				f(e)
 			}
 		}
 	}
-	return es
 }
 
 // Neighbourhood returns all immediate neighbours of the given node.
@@ -151,14 +447,7 @@ func (m *World) Neighbourhood(n graph.Node) []graph.Node {
 }
 
 func (m *World) neighbourhood(n int) []int {
-	ns := make([]int, 0, 0)
-	for i, p := range m.array[n] {
-		if p == 1 {
-			ns = append(ns, i)
-		}
-	}
-
-	return ns
+	return m.edges.neighbourhood(n)
 }
 
 func (m *World) toNodes(p path) []graph.Node {
@@ -205,44 +494,63 @@ func (m *World) localClusteringCoeffFor(n int) float64 {
 	}
 
 	ns = append(ns, n)
+	sort.Ints(ns)
 	numLinks := 0
 
 	for _, n1 := range ns {
-		for _, n2 := range ns {
-			if m.hasEdge(n1, n2) {
-				numLinks++
-			}
-		}
+		numLinks += countCommon(m.neighbourhood(n1), ns)
 	}
 
 	return float64(numLinks) / float64((len(ns) * (len(ns) - 1)))
 }
 
+// countCommon returns the number of elements found in both sorted slices.
+func countCommon(p, q []int) int {
+	count := 0
+	for i, j := 0, 0; i < len(p) && j < len(q); {
+		switch {
+		case p[i] < q[j]:
+			i++
+		case p[i] > q[j]:
+			j++
+		default:
+			count++
+			i++
+			j++
+		}
+	}
+	return count
+}
+
 // RandomWalk returns a random walk of the given length starting at the given node.
+// In a weighted world, the probability of taking an edge is inversely proportional
+// to its weight, i.e. costly edges are taken less often.
+//
+// RandomWalk draws from the world's random number generator, and thus must not
+// be called concurrently. Agents draw their walks from their own generators.
 func (m *World) RandomWalk(length int, from graph.Node) Walk {
-	return m.toNodes(m.randomPath(length, m.toInt[from.String()]))
+	return m.toNodes(m.randomPath(m.rand, length, m.toInt[from.String()]))
 }
 
 // KShortestPaths computes at most _k_ shortest paths between the given nodes.
-// It implements Dijkstra's algorithm.
+// Paths are ranked by the sum of their edge weights. By default the paths are
+// loopless and computed with Yen's algorithm; see WithPathAlgorithm. There are
+// no paths if to is not reachable from from.
 func (m *World) KShortestPaths(k int, from graph.Node, to graph.Node) []Walk {
-	ps := m.kShortestPaths(k, m.toInt[from.String()], m.toInt[to.String()])
+	ps := m.kPaths(k, m.toInt[from.String()], m.toInt[to.String()])
 	ns := make([]Walk, len(ps), len(ps))
 
 	for i := 0; i < len(ps); i++ {
 		ns[i] = m.toNodes(ps[i])
 	}
-
-	if len(ps) == 0 {
-		return []Walk{{from}}
-	}
 	return ns
 }
 
-// ShortestPathsLens returns the lenght of shortest paths between all nodes.
-// It implements Floyd-Warshall algorithm.
-func (m *World) ShortestPathsLens() [][]int {
-	distArray := m.initializeShortestPaths()
+// ShortestPathsCosts returns the cost of the cheapest paths between all nodes,
+// where the cost of a path is the sum of its edge weights. Unreachable nodes
+// are math.Inf(1) apart. It implements Floyd-Warshall algorithm.
+func (m *World) ShortestPathsCosts() [][]float64 {
+	distArray := m.initializeShortestPathsCosts()
 
 	for k := 0; k < len(distArray); k++ {
 		for i := 0; i < len(distArray); i++ {
@@ -258,21 +566,21 @@ func (m *World) ShortestPathsLens() [][]int {
 	return distArray
 }
 
-func (m *World) initializeShortestPaths() [][]int {
-	distArray := make([][]int, 0, 0)
+func (m *World) initializeShortestPathsCosts() [][]float64 {
+	distArray := make([][]float64, m.n, m.n)
 
-	for i := 0; i < len(m.array); i++ {
-		row := make([]int, 0, 0)
-		for j := 0; j < len(m.array); j++ {
-			if i == j {
-				row = append(row, 0)
-			} else if m.hasEdge(i, j) {
-				row = append(row, 1)
-			} else {
-				row = append(row, math.MaxUint32)
+	for i := 0; i < m.n; i++ {
+		distArray[i] = make([]float64, m.n, m.n)
+		for j := 0; j < m.n; j++ {
+			if i != j {
+				distArray[i][j] = math.Inf(1)
+			}
+		}
+		for _, j := range m.neighbourhood(i) {
+			if i != j {
+				distArray[i][j] = m.weight(i, j)
 			}
 		}
-		distArray = append(distArray, row)
 	}
 	return distArray
 }
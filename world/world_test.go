@@ -18,24 +18,170 @@ func Test_NewWorld(t *testing.T) {
 	assert.Len(t, m.neighbourhood(1), 2)
 	assert.Len(t, m.neighbourhood(2), 2)
 
-	assert.Equal(t, m.array[0], []int{0, 1, 1})
-	assert.Equal(t, m.array[1], []int{1, 0, 1})
-	assert.Equal(t, m.array[2], []int{1, 1, 0})
+	assert.Equal(t, []int{1, 2}, m.neighbourhood(0))
+	assert.Equal(t, []int{0, 2}, m.neighbourhood(1))
+	assert.Equal(t, []int{0, 1}, m.neighbourhood(2))
+}
+
+func Test_WithAdjacencyMatrix(t *testing.T) {
+	g := ring.NewGraph(1, 0.0).WithSeed(42).WithNodes(5).WithShortEdges()
+	m1 := NewWorld(g)
+	m2 := NewWorld(g).WithAdjacencyMatrix()
+
+	assert.Equal(t, adjacencyMatrix{
+		{0, 1, 0, 0, 1},
+		{1, 0, 1, 0, 0},
+		{0, 1, 0, 1, 0},
+		{0, 0, 1, 0, 1},
+		{1, 0, 0, 1, 0}}, m2.edges)
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, m1.neighbourhood(i), m2.neighbourhood(i))
+	}
+	assert.ElementsMatch(t, m1.Edges(), m2.Edges())
+}
+
+func Test_AddRemoveEdges(t *testing.T) {
+	g := ring.NewGraph(1, 0.0).WithSeed(42).WithNodes(5).WithShortEdges()
+	nodes := g.Nodes()
+	m := NewWorld(g)
+
+	m.AddEdges([]graph.Edge{graph.TupleEdge{nodes[0], nodes[2]}, graph.TupleEdge{nodes[0], nodes[3]}})
+	assert.Equal(t, []int{1, 2, 3, 4}, m.neighbourhood(0))
+	assert.True(t, m.HasEdge(nodes[0], nodes[2]))
+	assert.False(t, m.HasEdge(nodes[2], nodes[0]))
+
+	m.RemoveEdges([]graph.Edge{graph.TupleEdge{nodes[0], nodes[1]}, graph.TupleEdge{nodes[0], nodes[2]}})
+	assert.Equal(t, []int{3, 4}, m.neighbourhood(0))
+	assert.Len(t, m.Edges(), 10)
+}
+
+func Test_adjacencyList_compact(t *testing.T) {
+	l := newAdjacencyList(2)
+	l.appendEdge(0, 1, 1)
+	l.appendEdge(1, 0, 1)
+	l.appendEdge(0, 1, 1)
+	l.compact()
+
+	assert.Equal(t, [][]int{{1}, {0}}, l.nodes)
+	assert.Nil(t, l.weights)
+}
+
+func Test_adjacencyList_weights(t *testing.T) {
+	l := newAdjacencyList(3)
+	l.appendEdge(0, 2, 1)
+	l.appendEdge(0, 1, 3)
+	l.appendEdge(0, 2, 2)
+	l.compact()
+
+	assert.Equal(t, []int{1, 2}, l.neighbourhood(0))
+	assert.Equal(t, []float64{3, 2}, l.weights[0])
+
+	l.addEdge(1, 0, 1)
+	l.addEdge(0, 0, 5)
+	l.removeEdge(0, 1)
+	assert.Equal(t, []int{0, 2}, l.neighbourhood(0))
+	assert.Equal(t, []int{0}, l.neighbourhood(1))
+	assert.Equal(t, []float64{5, 2}, l.weights[0])
+	assert.Equal(t, 2.0, l.weight(0, 2))
+	assert.Equal(t, 1.0, l.weight(1, 0))
+}
+
+func Test_Weighted_Grid(t *testing.T) {
+	g := grid.NewGraph(4, 4).WithSeed(42).WithAllNodes().WithShortEdges(1).WithDistantEdges(1, 2).WithWeights()
+	m := NewWorld(g)
+
+	assert.True(t, m.Weighted())
+	for _, e := range m.Edges() {
+		assert.Equal(t, float64(e.From().(grid.Position).Distance(e.To())), graph.Weight(e))
+	}
+	assert.False(t, NewWorld(grid.NewGraph(4, 4).WithAllNodes().WithShortEdges(1)).Weighted())
+}
+
+func Test_Weighted_KShortestPaths(t *testing.T) {
+	g := ring.NewGraph(1, 0).WithSeed(42).WithNodes(4).WithShortEdges()
+	nodes := g.Nodes()
+	m := NewWorld(g)
+	m.AddEdges([]graph.Edge{graph.WeightedTupleEdge{TupleEdge: graph.TupleEdge{nodes[0], nodes[1]}, Cost: 4}})
+
+	assert.True(t, m.Weighted())
+	assert.Equal(t, 4.0, m.Weight(nodes[0], nodes[1]))
+	assert.EqualValues(t, []Walk{
+		{nodes[0], nodes[3], nodes[2], nodes[1]},
+		{nodes[0], nodes[1]},
+	}, m.KShortestPaths(2, nodes[0], nodes[1]))
+}
+
+// attributes maps node names to their numeric attributes.
+type attributes map[string]map[string]float64
+
+func (as attributes) Attributes(n graph.Node) map[string]float64 {
+	return as[n.String()]
+}
+
+func Test_WithAttributes(t *testing.T) {
+	m := NewWorld(ring.NewGraph(1, 0).WithNodes(2).WithShortEdges())
+	m.AddContext(graph.IntNode(0), Context{"a": 1, "b": 1})
+
+	m.WithAttributes(attributes{"0": {"b": 2}, "1": {"c": 3}, "7": {"d": 4}})
+	assert.Equal(t, Context{"a": 1, "b": 2}, m.Context(graph.IntNode(0)))
+	assert.Equal(t, map[string]float64{"c": 3}, m.Attributes(graph.IntNode(1)))
+	assert.False(t, m.HasNode(graph.IntNode(7)))
+}
+
+func Test_Weighted_NonPositive(t *testing.T) {
+	nodes := []graph.Node{graph.IntNode(0), graph.IntNode(1)}
+	free := []graph.Edge{graph.WeightedIntEdge{IntEdge: graph.IntEdge{0, 1}, Cost: 0}}
+
+	assert.Panics(t, func() { NewWorld(subgraph{nodes: nodes, edges: free}) })
+
+	m := NewWorld(subgraph{nodes: nodes}).WithAdjacencyMatrix()
+	assert.Panics(t, func() { m.AddEdges(free) })
+	assert.Panics(t, func() {
+		m.AddEdges([]graph.Edge{graph.WeightedIntEdge{IntEdge: graph.IntEdge{1, 0}, Cost: -1}})
+	})
+	assert.False(t, m.HasEdge(nodes[0], nodes[1]))
+}
+
+func Test_Weighted_RandomWalk(t *testing.T) {
+	g := ring.NewGraph(1, 0).WithSeed(42).WithNodes(3).WithShortEdges()
+	nodes := g.Nodes()
+	m := NewWorld(g).WithSeed(42)
+	m.AddEdges([]graph.Edge{graph.WeightedTupleEdge{TupleEdge: graph.TupleEdge{nodes[0], nodes[1]}, Cost: 99}})
+
+	visits := 0
+	for i := 0; i < 1000; i++ {
+		if m.RandomWalk(2, nodes[0]).End() == nodes[1] {
+			visits++
+		}
+	}
+	assert.InDelta(t, 10, visits, 10)
+}
+
+func Test_ShortestPathsCosts(t *testing.T) {
+	g := ring.NewGraph(1, 0).WithSeed(42).WithNodes(5).WithShortEdges().WithWeights()
+	m := NewWorld(g)
+	assert.Equal(t, 30.0, sumCosts(m.ShortestPathsCosts()))
+
+	m.AddEdges([]graph.Edge{graph.WeightedTupleEdge{TupleEdge: graph.TupleEdge{g.Nodes()[0], g.Nodes()[1]}, Cost: 4}})
+	ds := m.ShortestPathsCosts()
+	assert.Equal(t, 4.0, ds[0][1])
+	assert.Equal(t, 1.0, ds[1][0])
 }
 
 func Test_hasEdge(t *testing.T) {
 	m1 := NewWorld(ring.NewGraph(1, 0.0).WithSeed(42).WithNodes(8).WithShortEdges()) // Only local edges
 
-	assert.Equal(t, m1.array[0][0], 0)
-	assert.Equal(t, m1.array[0][1], 1)
-	assert.Equal(t, m1.array[1][4], 0)
-	assert.Equal(t, m1.array[3][0], 0)
+	assert.False(t, m1.hasEdge(0, 0))
+	assert.True(t, m1.hasEdge(0, 1))
+	assert.False(t, m1.hasEdge(1, 4))
+	assert.False(t, m1.hasEdge(3, 0))
 
 	m2 := NewWorld(ring.NewGraph(1, 0.0).WithSeed(42).WithNodes(8).WithShortEdges().WithDistantEdges()) // With distant edges and beta=0
-	assert.Equal(t, m2.array[0][0], 0)
-	assert.Equal(t, m2.array[0][1], 1)
-	assert.Equal(t, m2.array[1][4], 0)
-	assert.Equal(t, m2.array[3][0], 0)
+	assert.False(t, m2.hasEdge(0, 0))
+	assert.True(t, m2.hasEdge(0, 1))
+	assert.False(t, m2.hasEdge(1, 4))
+	assert.False(t, m2.hasEdge(3, 0))
 }
 
 func Test_localClusteringCoeffFor_With4(t *testing.T) {
@@ -47,10 +193,10 @@ func Test_localClusteringCoeffFor_With4(t *testing.T) {
 func Test_localClusteringCoeffFor_With3(t *testing.T) {
 	m2 := NewWorld(ring.NewGraph(1, 0.0).WithSeed(42).WithNodes(3).WithShortEdges())
 	assert.Len(t, m2.neighbourhood(0), 2)
-	assert.Equal(t, m2.array[0], []int{0, 1, 1})
-	assert.Equal(t, m2.array[0][1], 1)
-	assert.Equal(t, m2.array[1][2], 1)
-	assert.Equal(t, m2.array[2][0], 1)
+	assert.Equal(t, []int{1, 2}, m2.neighbourhood(0))
+	assert.True(t, m2.hasEdge(0, 1))
+	assert.True(t, m2.hasEdge(1, 2))
+	assert.True(t, m2.hasEdge(2, 0))
 	assert.Equal(t, m2.localClusteringCoeffFor(0), 1.0)
 }
 
@@ -102,6 +248,12 @@ func Test_KShortestPaths(t *testing.T) {
 	m := NewWorld(g)
 
 	w := m.KShortestPaths(2, g.Nodes()[0], g.Nodes()[1])
+	assert.EqualValues(t, []Walk{
+		Walk{g.Nodes()[0], g.Nodes()[1]},
+		Walk{g.Nodes()[0], g.Nodes()[2], g.Nodes()[1]},
+	}, w)
+
+	w = m.WithPathAlgorithm(Walks).KShortestPaths(2, g.Nodes()[0], g.Nodes()[1])
 	assert.EqualValues(t, []Walk{
 		Walk{g.Nodes()[0], g.Nodes()[1]},
 		Walk{g.Nodes()[0], g.Nodes()[4], g.Nodes()[1]},
@@ -141,6 +293,20 @@ func Test_ShortestPaths_5(t *testing.T) {
 	assert.Equal(t, 30, sumDistances(ds))
 }
 
+// newMatrixWorld creates a world over the nodes 0..n-1 with
+// the edges given by the adjacency matrix.
+func newMatrixWorld(array [][]int) *World {
+	m := NewWorld(ring.NewGraph(1, 0).WithSeed(42).WithNodes(len(array)))
+	edges := newAdjacencyMatrix(len(array))
+	for i := range array {
+		for j := range array[i] {
+			edges[i][j] = float64(array[i][j])
+		}
+	}
+	m.edges = edges
+	return m
+}
+
 func sumDistances(m [][]int) int {
 	sum := 0
 	for i := 0; i < len(m); i++ {
@@ -150,3 +316,13 @@ func sumDistances(m [][]int) int {
 	}
 	return sum
 }
+
+func sumCosts(m [][]float64) float64 {
+	sum := 0.0
+	for i := 0; i < len(m); i++ {
+		for j := 0; j < len(m); j++ {
+			sum = sum + m[i][j]
+		}
+	}
+	return sum
+}